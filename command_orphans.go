@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"github.com/mitchellh/cli"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"gopkg.in/yaml.v2"
+)
+
+// OrphansCommand finds resources that are clearly unused
+// and prints them as a yaml configuration for the wipe command.
+type OrphansCommand struct {
+	Ui          cli.Ui
+	client      *AWSClient
+	outFileName string
+	infos       map[string]ResourceInfo
+}
+
+func (c *OrphansCommand) Run(args []string) int {
+	c.infos = map[string]ResourceInfo{}
+	for _, info := range getResourceInfos(&WipeCommand{client: c.client}) {
+		c.infos[info.TerraformType] = info
+	}
+
+	orphans := map[string]yamlCfg{}
+	add := func(ttype string, ids []*string) {
+		if len(ids) == 0 {
+			return
+		}
+
+		// IDs in the configuration are regular expressions
+		regexes := []*string{}
+		for _, id := range ids {
+			regexes = append(regexes, aws.String("^"+regexp.QuoteMeta(*id)+"$"))
+		}
+		orphans[ttype] = yamlCfg{Ids: regexes}
+	}
+
+	add("aws_ebs_volume", c.getUnusedVolumes())
+	add("aws_eip", c.getUnusedEips())
+	add("aws_network_interface", c.getUnusedNetworkInterfaces())
+	add("aws_security_group", c.getUnusedSecurityGroups())
+	add("aws_ebs_snapshot", c.getUnusedSnapshots())
+	add("aws_ami", c.getUnusedAmis())
+	add("aws_s3_bucket", c.getEmptyBuckets())
+
+	outYaml, err := yaml.Marshal(&orphans)
+	check(err)
+
+	if c.outFileName != "" {
+		err = ioutil.WriteFile(c.outFileName, outYaml, 0644)
+		check(err)
+	} else {
+		fmt.Print(string(outYaml))
+	}
+
+	return 0
+}
+
+func (c *OrphansCommand) Help() string {
+	return Help()
+}
+
+func (c *OrphansCommand) Synopsis() string {
+	return "Print a yaml configuration of unused AWS resources"
+}
+
+// list lists all resources of a type via its resource info,
+// optionally with a different input for the describe call.
+func (c *OrphansCommand) list(ttype string, input interface{}) interface{} {
+	info := c.infos[ttype]
+	if input != nil {
+		info.DescribeFnInput = input
+	}
+	res, err := listResources(info, true)
+	check(err)
+	return res.raw
+}
+
+func (c *OrphansCommand) getUnusedVolumes() []*string {
+	ids := []*string{}
+
+	for _, v := range c.list("aws_ebs_volume", nil).(*ec2.DescribeVolumesOutput).Volumes {
+		if *v.State == ec2.VolumeStateAvailable {
+			ids = append(ids, v.VolumeId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) getUnusedEips() []*string {
+	ids := []*string{}
+
+	for _, a := range c.list("aws_eip", nil).(*ec2.DescribeAddressesOutput).Addresses {
+		// addresses of EC2-Classic have no allocation ID
+		if a.AllocationId != nil && a.AssociationId == nil {
+			ids = append(ids, a.AllocationId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) getUnusedNetworkInterfaces() []*string {
+	ids := []*string{}
+
+	for _, eni := range c.list("aws_network_interface", nil).(*ec2.DescribeNetworkInterfacesOutput).NetworkInterfaces {
+		if *eni.Status == ec2.NetworkInterfaceStatusAvailable {
+			ids = append(ids, eni.NetworkInterfaceId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) getUnusedSecurityGroups() []*string {
+	ids := []*string{}
+
+	inUse := map[string]bool{}
+	for _, eni := range c.list("aws_network_interface", nil).(*ec2.DescribeNetworkInterfacesOutput).NetworkInterfaces {
+		for _, g := range eni.Groups {
+			inUse[*g.GroupId] = true
+		}
+	}
+
+	for _, g := range c.list("aws_security_group", nil).(*ec2.DescribeSecurityGroupsOutput).SecurityGroups {
+		// default groups can't be deleted
+		if *g.GroupName != "default" && !inUse[*g.GroupId] {
+			ids = append(ids, g.GroupId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) getUnusedSnapshots() []*string {
+	ids := []*string{}
+
+	volumes := map[string]bool{}
+	for _, v := range c.list("aws_ebs_volume", nil).(*ec2.DescribeVolumesOutput).Volumes {
+		volumes[*v.VolumeId] = true
+	}
+
+	inUse := map[string]bool{}
+	for _, img := range c.listOwnImages() {
+		for _, bdm := range img.BlockDeviceMappings {
+			if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
+				inUse[*bdm.Ebs.SnapshotId] = true
+			}
+		}
+	}
+
+	snapshots := c.list("aws_ebs_snapshot", &ec2.DescribeSnapshotsInput{
+		OwnerIds: aws.StringSlice([]string{"self"}),
+	}).(*ec2.DescribeSnapshotsOutput).Snapshots
+
+	for _, s := range snapshots {
+		if !volumes[*s.VolumeId] && !inUse[*s.SnapshotId] {
+			ids = append(ids, s.SnapshotId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) getUnusedAmis() []*string {
+	ids := []*string{}
+
+	inUse := map[string]bool{}
+	for _, r := range c.list("aws_instance", nil).(*ec2.DescribeInstancesOutput).Reservations {
+		for _, in := range r.Instances {
+			if *in.State.Name != ec2.InstanceStateNameTerminated {
+				inUse[*in.ImageId] = true
+			}
+		}
+	}
+
+	lcs := c.list("aws_launch_configuration", nil).(*autoscaling.DescribeLaunchConfigurationsOutput).LaunchConfigurations
+	for _, lc := range lcs {
+		inUse[*lc.ImageId] = true
+	}
+
+	for _, img := range c.listOwnImages() {
+		if !inUse[*img.ImageId] {
+			ids = append(ids, img.ImageId)
+		}
+	}
+	return ids
+}
+
+func (c *OrphansCommand) listOwnImages() []*ec2.Image {
+	return c.list("aws_ami", &ec2.DescribeImagesInput{
+		Owners: aws.StringSlice([]string{"self"}),
+	}).(*ec2.DescribeImagesOutput).Images
+}
+
+// getEmptyBuckets returns the buckets in the current region without any objects (or versions of objects).
+func (c *OrphansCommand) getEmptyBuckets() []*string {
+	ids := []*string{}
+
+	region := *c.client.s3conn.Config.Region
+	for _, b := range c.list("aws_s3_bucket", nil).(*s3.ListBucketsOutput).Buckets {
+		loc, err := c.client.s3conn.GetBucketLocation(&s3.GetBucketLocationInput{
+			Bucket: b.Name,
+		})
+		if err != nil {
+			continue
+		}
+
+		// the location of buckets in us-east-1 is empty
+		bucketRegion := "us-east-1"
+		if loc.LocationConstraint != nil && *loc.LocationConstraint != "" {
+			bucketRegion = *loc.LocationConstraint
+		}
+		if bucketRegion != region {
+			continue
+		}
+
+		vs, err := c.client.s3conn.ListObjectVersions(&s3.ListObjectVersionsInput{
+			Bucket:  b.Name,
+			MaxKeys: aws.Int64(1),
+		})
+		if err != nil {
+			continue
+		}
+
+		if len(vs.Versions) == 0 && len(vs.DeleteMarkers) == 0 {
+			ids = append(ids, b.Name)
+		}
+	}
+	return ids
+}
@@ -21,11 +21,119 @@ import (
 	"reflect"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/emr"
+	"sort"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"time"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/aws-sdk-go/service/codecommit"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/codepipeline"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/glacier"
+	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/waf"
+	"github.com/aws/aws-sdk-go/service/wafregional"
+	"github.com/aws/aws-sdk-go/service/opsworks"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/directoryservice"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"strings"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"math"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"github.com/aws/aws-sdk-go/aws/session"
 )
 
 type yamlCfg struct {
 	Ids  []*string `yaml:",omitempty"`
 	Tags map[string]string `yaml:",omitempty"`
+	// filters for certificates
+	DomainNames   []*string `yaml:"domain_names,omitempty"`
+	ExpiresBefore string    `yaml:"expires_before,omitempty"`
+	IncludeInUse  bool      `yaml:"include_in_use,omitempty"`
+	// filter for resources whose IDs differ from their names
+	Names []*string `yaml:"names,omitempty"`
+	// stop running executions of step functions state machines before deletion
+	StopExecutions bool `yaml:"stop_executions,omitempty"`
+	// CodeCommit repositories are only deleted with this explicit opt-in, as source code is lost
+	DeleteSourceCode bool `yaml:"delete_source_code,omitempty"`
+	// filter for SSM parameters by the prefix of their path (e.g. /preview/pr-123/)
+	PathPrefixes []*string `yaml:"path_prefixes,omitempty"`
+	// delete the S3 source bundles of Elastic Beanstalk application versions as well
+	DeleteSourceBundle bool `yaml:"delete_source_bundle,omitempty"`
+	// filter for OpsWorks stacks by their region
+	Regions []*string `yaml:"regions,omitempty"`
+	// IAM resources on these paths are never deleted, in addition to the built-in protected ones
+	ProtectedPaths []*string `yaml:"protected_paths,omitempty"`
+	// delete protected IAM resources as well
+	IncludeProtected bool `yaml:"include_protected,omitempty"`
+	// delete default resources created by AWS as well (e.g. the default VPC)
+	IncludeDefaults bool `yaml:"include_defaults,omitempty"`
+	// delete all resources inside a VPC together with it
+	Cascade bool `yaml:"cascade,omitempty"`
+	// filter for resources which did nothing according to CloudWatch
+	Idle *idleCfg `yaml:"idle,omitempty"`
+	// delete RDS instances without a final snapshot
+	SkipFinalSnapshot bool `yaml:"skip_final_snapshot,omitempty"`
+	// filter for resources created by principals whose ARNs match, according to CloudTrail
+	CreatedBy []*string `yaml:"created_by,omitempty"`
+	// filter for IAM users, roles and access keys which weren't used for this long (e.g. 90d)
+	LastUsedBefore string `yaml:"last_used_before,omitempty"`
+	// match IAM principals which have never been used by last_used_before as well
+	NeverUsed bool `yaml:"never_used,omitempty"`
+	// abort before deleting anything if more resources of this type would be deleted
+	MaxDelete int `yaml:"max_delete,omitempty"`
+	// abort before deleting anything if more than this percentage of the existing resources would be deleted
+	MaxDeletePercent int `yaml:"max_delete_percent,omitempty"`
+}
+
+// limits for all resource types, at the top level of the configuration
+type limitsCfg struct {
+	// maximum number of resources deleted in total
+	MaxDelete int `yaml:"max_delete,omitempty"`
+	// maximum percentage of the existing resources of each type
+	MaxDeletePercent int `yaml:"max_delete_percent,omitempty"`
+}
+
+type idleCfg struct {
+	Metric    string  `yaml:"metric,omitempty"`
+	Statistic string  `yaml:"statistic,omitempty"`
+	Below     float64 `yaml:"below,omitempty"`
+	Days      int     `yaml:"days,omitempty"`
+}
+
+// idleMetric is the CloudWatch metric which tells if a resource of a type is idle by default
+type idleMetric struct {
+	namespace string
+	dimension string
+	metric    string
+	statistic string
+	// a resource is idle if the metric is below this value
+	below float64
+}
+
+var idleMetrics = map[string]idleMetric{
+	"aws_instance":    {"AWS/EC2", "InstanceId", "CPUUtilization", cloudwatch.StatisticAverage, 2},
+	"aws_elb":         {"AWS/ELB", "LoadBalancerName", "RequestCount", cloudwatch.StatisticSum, 1},
+	"aws_db_instance": {"AWS/RDS", "DBInstanceIdentifier", "DatabaseConnections", cloudwatch.StatisticMaximum, 1},
+	"aws_nat_gateway": {"AWS/NATGateway", "NatGatewayId", "BytesOutToDestination", cloudwatch.StatisticSum, 1},
 }
 
 type WipeCommand struct {
@@ -39,6 +147,27 @@ type WipeCommand struct {
 	deleteCfg     map[string]yamlCfg
 	deleteOut     map[string]yamlCfg
 	outFileName   string
+	// creators of resources found in CloudTrail, by resource ID
+	creators      map[string]string
+	creatorsFile  string
+	// time of the last CloudTrail lookup, by region
+	lastLookup    map[string]time.Time
+	// CloudTrail clients of all regions, in which roles can be assumed
+	roleTrails    []*cloudtrail.CloudTrail
+	// reference time of the last_used_before filter
+	now           time.Time
+	// limits given as flags override those in the configuration
+	maxDelete     int
+	maxDeletePercent int
+	limits        limitsCfg
+	// only count the resources which would be deleted, without any output
+	counting      bool
+	// results of the filters by type and ID, as some need API calls
+	matches       map[string]bool
+	unused        map[string]bool
+	// number of resources that would be deleted and that exist, by type
+	counts        map[string]int
+	existing      map[string]int
 }
 
 type Resources struct {
@@ -47,13 +176,23 @@ type Resources struct {
 	ids   []*string
 	attrs []*map[string]string
 	tags  []*map[string]string
-	raw   interface{}
+	// additional infos printed for each resource (e.g. size of a cluster)
+	details []*map[string]string
+	// deletes a resource via the AWS API, if its type is not supported by the terraform provider
+	deleteFn func(id *string) error
+	// minimum time between two deletions, for APIs with a low rate limit
+	rateLimit time.Duration
+	// default resources created by AWS, left out when listing
+	defaults    map[string]string
+	defaultTags map[string]*map[string]string
+	raw         interface{}
 }
 
 type Resource struct {
-	id    *string
-	attrs *map[string]string
-	tags  *map[string]string
+	id      *string
+	attrs   *map[string]string
+	tags    *map[string]string
+	details *map[string]string
 }
 
 type ResourceInfo struct {
@@ -76,24 +215,68 @@ type AWSClient struct {
 	kmsconn         *kms.KMS
 	s3conn			*s3.S3
 	stsconn         *sts.STS
+	esconn          *elasticsearchservice.ElasticsearchService
+	redshiftconn    *redshift.Redshift
+	emrconn         *emr.EMR
+	acmconn         *acm.ACM
+	cloudfrontconn  *cloudfront.CloudFront
+	apigatewayconn  *apigateway.APIGateway
+	kinesisconn     *kinesis.Kinesis
+	firehoseconn    *firehose.Firehose
+	sfnconn         *sfn.SFN
+	codebuildconn   *codebuild.CodeBuild
+	codecommitconn  *codecommit.CodeCommit
+	codedeployconn  *codedeploy.CodeDeploy
+	codepipelineconn *codepipeline.CodePipeline
+	ssmconn         *ssm.SSM
+	beanstalkconn   *elasticbeanstalk.ElasticBeanstalk
+	glacierconn     *glacier.Glacier
+	simpledbconn    *simpledb.SimpleDB
+	sesconn         *ses.SES
+	wafconn         *waf.WAF
+	wafregionalconn *wafregional.WAFRegional
+	opsworksconn    *opsworks.OpsWorks
+	cognitoconn     *cognitoidentity.CognitoIdentity
+	dsconn          *directoryservice.DirectoryService
+	lightsailconn   *lightsail.Lightsail
+	dmsconn         *databasemigrationservice.DatabaseMigrationService
+	lambdaconn      *lambda.Lambda
+	rdsconn         *rds.RDS
+	elbv2conn       *elbv2.ELBV2
+	cloudwatchconn  *cloudwatch.CloudWatch
+	cloudtrailconn  *cloudtrail.CloudTrail
+	// creates clients for other regions
+	sess            *session.Session
 }
 
 func (c *WipeCommand) Run(args []string) int {
 	c.deleteCfg = map[string]yamlCfg{}
 	c.deleteOut = map[string]yamlCfg{}
-
-	c.resourceInfos = getResourceInfos(c)
+	c.matches = map[string]bool{}
+	c.unused = map[string]bool{}
+	c.lastLookup = map[string]time.Time{}
+	c.now = time.Now()
 
 	if len(args) == 1 {
 		data, err := ioutil.ReadFile(args[0])
 		check(err)
-		err = yaml.Unmarshal([]byte(data), &c.deleteCfg)
+		err = c.loadConfig(data)
 		check(err)
 	} else {
 		fmt.Println(Help())
 		return 1
 	}
 
+	if c.maxDelete > 0 {
+		c.limits.MaxDelete = c.maxDelete
+	}
+	if c.maxDeletePercent > 0 {
+		c.limits.MaxDeletePercent = c.maxDeletePercent
+	}
+
+	// the resource infos depend on the configuration
+	c.resourceInfos = getResourceInfos(c)
+
 	if c.dryRun {
 		c.Ui.Output("INFO: This is a test run, nothing will be deleted!")
 	} else if !c.forceDelete {
@@ -116,15 +299,84 @@ func (c *WipeCommand) Run(args []string) int {
 		for _, rInfo := range c.resourceInfos {
 			if ttype == rInfo.TerraformType {
 				isTerraformType = true
-				rInfo.DeleteFn(listResources(rInfo))
 			}
 		}
 		if !isTerraformType {
 			fmt.Printf("Err: Unsupported resource type '%s' found in '%s'\n", ttype, args[0])
 			return 1
 		}
+		if _, ok := idleMetrics[ttype]; c.deleteCfg[ttype].Idle != nil && !ok {
+			fmt.Printf("Err: The idle filter isn't supported for resource type '%s'\n", ttype)
+			return 1
+		}
+		if c.deleteCfg[ttype].Idle != nil {
+			if err := c.validateIdle(ttype); err != nil {
+				fmt.Printf("Err: Invalid idle filter for resource type '%s': %s\n", ttype, err)
+				return 1
+			}
+		}
+		if _, ok := createEvents[ttype]; len(c.deleteCfg[ttype].CreatedBy) > 0 && !ok {
+			fmt.Printf("Err: The created_by filter isn't supported for resource type '%s'\n", ttype)
+			return 1
+		}
+		if expiresBefore := c.deleteCfg[ttype].ExpiresBefore; expiresBefore != "" {
+			if _, err := parseExpiresBefore(expiresBefore); err != nil {
+				fmt.Printf("Err: Invalid expires_before '%s' for resource type '%s'\n", expiresBefore, ttype)
+				return 1
+			}
+		}
+		if lastUsedBefore := c.deleteCfg[ttype].LastUsedBefore; lastUsedBefore != "" {
+			if !lastUsedTypes[ttype] {
+				fmt.Printf("Err: The last_used_before filter isn't supported for resource type '%s'\n", ttype)
+				return 1
+			}
+			age, err := parseAge(lastUsedBefore)
+			if err != nil {
+				fmt.Printf("Err: Invalid last_used_before '%s' for resource type '%s'\n", lastUsedBefore, ttype)
+				return 1
+			}
+			// the last use of roles is looked up in CloudTrail
+			if ttype == "aws_iam_role" && age > cloudTrailRetention {
+				fmt.Printf("Err: last_used_before can't exceed 90d for resource type '%s'\n", ttype)
+				return 1
+			}
+		}
+	}
+
+	// with limits, everything is counted in a test run first, so that nothing is deleted if one is exceeded
+	if c.hasLimits() && !c.dryRun {
+		c.counting = true
+		c.dryRun = true
+		err := c.deleteAll()
+		check(err)
+		c.counting = false
+		c.dryRun = false
+
+		if violations := c.checkLimits(); len(violations) > 0 {
+			for _, v := range violations {
+				fmt.Printf("Err: %s\n", v)
+			}
+			fmt.Println("Aborted, nothing has been deleted.")
+			return 1
+		}
+	}
+
+	exitStatus := 0
+	if err := c.deleteAll(); err != nil {
+		fmt.Printf("Err: %s\n", err)
+		fmt.Println("Aborted, resources of types listed before have been deleted already.")
+		exitStatus = 1
 	}
 
+	if c.dryRun {
+		for _, v := range c.checkLimits() {
+			fmt.Printf("WARN: %s\n", v)
+		}
+	}
+
+	if c.creators != nil {
+		c.saveCreators()
+	}
 
 	if c.outFileName != "" {
 		outYaml, err := yaml.Marshal(&c.deleteOut)
@@ -135,7 +387,159 @@ func (c *WipeCommand) Run(args []string) int {
 		check(err)
 	}
 
-	return 0
+	return exitStatus
+}
+
+// loadConfig reads the configuration of resource types and the global limits next to them.
+func (c *WipeCommand) loadConfig(data []byte) error {
+	err := yaml.Unmarshal(data, &c.limits)
+	if err != nil {
+		return err
+	}
+
+	cfg := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &cfg)
+	if err != nil {
+		return err
+	}
+	delete(cfg, "max_delete")
+	delete(cfg, "max_delete_percent")
+
+	data, err = yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, &c.deleteCfg)
+}
+
+// deleteAll deletes the resources in the order of the resource infos,
+// so that dependent resources are deleted first. With limits, it stops before
+// the first type which would exceed one.
+func (c *WipeCommand) deleteAll() error {
+	c.counts = map[string]int{}
+	c.existing = map[string]int{}
+
+	for _, rInfo := range c.resourceInfos {
+		if _, ok := c.deleteCfg[rInfo.TerraformType]; ok {
+			res, err := listResources(rInfo, c.deleteCfg[rInfo.TerraformType].IncludeDefaults)
+			if err != nil {
+				c.printListingFailed(rInfo.TerraformType, err)
+				continue
+			}
+			c.existing[rInfo.TerraformType] = countExisting(res)
+
+			// resources created since counting could exceed a limit
+			if !c.dryRun && c.hasLimits() {
+				if violations := c.countType(rInfo, res); len(violations) > 0 {
+					return fmt.Errorf("Deleting resources of type '%s' exceeds limits:\n\t%s",
+						rInfo.TerraformType, strings.Join(violations, "\n\t"))
+				}
+			}
+
+			c.printProtected(rInfo.TerraformType, c.configuredDefaults(res))
+			rInfo.DeleteFn(res)
+		}
+	}
+	return nil
+}
+
+// countType counts the resources of a type which would be deleted and returns the limits
+// they exceed together with the types deleted so far. It is called before deleting the
+// type, as some delete functions change other resources first (e.g. revoke rules).
+func (c *WipeCommand) countType(rInfo ResourceInfo, res Resources) []string {
+	counts := map[string]int{}
+	for ttype, n := range c.counts {
+		counts[ttype] = n
+	}
+
+	c.counting = true
+	c.dryRun = true
+	rInfo.DeleteFn(res)
+	c.counting = false
+	c.dryRun = false
+
+	violations := c.checkLimits()
+	c.counts = counts
+	return violations
+}
+
+// configuredDefaults returns the default resources matching the ids or tags of the configuration,
+// as only those would have been deleted without include_defaults.
+func (c *WipeCommand) configuredDefaults(res Resources) map[string]string {
+	defaults := map[string]string{}
+	for id, reason := range res.defaults {
+		if c.matchesIdsOrTags(res.ttype, aws.String(id), res.defaultTags[id]) {
+			defaults[id] = reason
+		}
+	}
+	return defaults
+}
+
+// countExisting returns the number of listed resources, as the IDs of some types
+// are those of other resources.
+func countExisting(res Resources) int {
+	switch raw := res.raw.(type) {
+	case *ec2.DescribeInstancesOutput:
+		n := 0
+		for _, r := range raw.Reservations {
+			n += len(r.Instances)
+		}
+		return n
+	}
+	return len(res.ids)
+}
+
+func (c *WipeCommand) hasLimits() bool {
+	if c.limits.MaxDelete > 0 || c.limits.MaxDeletePercent > 0 {
+		return true
+	}
+	for _, cfg := range c.deleteCfg {
+		if cfg.MaxDelete > 0 || cfg.MaxDeletePercent > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLimits returns which limits the counted resources exceed.
+func (c *WipeCommand) checkLimits() []string {
+	violations := []string{}
+
+	ttypes := []string{}
+	for ttype := range c.counts {
+		ttypes = append(ttypes, ttype)
+	}
+	sort.Strings(ttypes)
+
+	total := 0
+	for _, ttype := range ttypes {
+		count := c.counts[ttype]
+		total += count
+
+		cfg := c.deleteCfg[ttype]
+		if cfg.MaxDelete > 0 && count > cfg.MaxDelete {
+			violations = append(violations, fmt.Sprintf(
+				"Deleting %d resources of type '%s' exceeds max_delete of %d", count, ttype, cfg.MaxDelete))
+		}
+
+		maxPercent := c.limits.MaxDeletePercent
+		if cfg.MaxDeletePercent > 0 {
+			maxPercent = cfg.MaxDeletePercent
+		}
+		// resources deleted together with others (e.g. policy attachments) are unknown beforehand
+		existing := c.existing[ttype]
+		if maxPercent > 0 && existing > 0 && count*100 > maxPercent*existing {
+			violations = append(violations, fmt.Sprintf(
+				"Deleting %d of %d resources of type '%s' exceeds max_delete_percent of %d%%",
+				count, existing, ttype, maxPercent))
+		}
+	}
+
+	if c.limits.MaxDelete > 0 && total > c.limits.MaxDelete {
+		violations = append(violations, fmt.Sprintf(
+			"Deleting %d resources in total exceeds max_delete of %d", total, c.limits.MaxDelete))
+	}
+	return violations
 }
 
 func (c *WipeCommand) Help() string {
@@ -146,26 +550,119 @@ func (c *WipeCommand) Synopsis() string {
 	return "Delete AWS resources via a yaml configuration"
 }
 
-func listResources(info ResourceInfo) Resources {
+// listResources lists all resources of a type. Default resources created by AWS
+// are left out, unless includeDefaults is set.
+func listResources(info ResourceInfo, includeDefaults bool) (Resources, error) {
 	ids := []*string{}
 	tags := []*map[string]string{}
+	defaults := map[string]string{}
+	defaultTags := map[string]*map[string]string{}
 
 	v := reflect.ValueOf(info.DescribeFn)
 	args := make([]reflect.Value, 1)
 	args[0] = reflect.ValueOf(info.DescribeFnInput)
 
 	raw := v.Call(args)
+	if err, ok := raw[1].Interface().(error); ok {
+		return Resources{}, err
+	}
 	descOutput := raw[0].Elem().FieldByName(info.DescribeOutputName)
 
-	if info.TerraformType != "aws_instance" {
+	// some outputs nest the list of resources in a struct (e.g. DistributionList.Items)
+	if descOutput.Kind() == reflect.Ptr {
+		descOutput = reflect.Indirect(descOutput)
+		if descOutput.IsValid() {
+			descOutput = descOutput.FieldByName("Items")
+		}
+	}
+
+	if info.TerraformType != "aws_instance" && descOutput.IsValid() {
 		for i := 0; i < descOutput.Len(); i++ {
 			bla := descOutput.Index(i)
-			ids = append(ids, aws.String(reflect.Indirect(bla).FieldByName(info.DeleteId).Elem().String()))
+
+			// some outputs are plain lists of names (e.g. StreamNames)
+			if reflect.Indirect(bla).Kind() == reflect.String {
+				ids = append(ids, aws.String(reflect.Indirect(bla).String()))
+				tags = append(tags, &map[string]string{})
+				continue
+			}
+			id := aws.String(reflect.Indirect(bla).FieldByName(info.DeleteId).Elem().String())
+			if reason := getDefault(bla); reason != "" && !includeDefaults {
+				defaults[*id] = reason
+				defaultTags[*id] = getTags(descOutput.Index(i))
+				continue
+			}
+
+			ids = append(ids, id)
 			tags = append(tags, getTags(descOutput.Index(i)))
 		}
 	}
+	return Resources{ttype: info.TerraformType, ids: ids, tags: tags, defaults: defaults,
+		defaultTags: defaultTags, raw: raw[0].Interface()}, nil
+}
 
-	return Resources{ttype: info.TerraformType, ids: ids, tags: tags, raw: raw[0].Interface()}
+// getDefault returns what kind of default resource created by AWS a resource is,
+// or an empty string if it isn't one.
+func getDefault(res reflect.Value) string {
+	switch r := res.Interface().(type) {
+	case *ec2.Vpc:
+		if r.IsDefault != nil && *r.IsDefault {
+			return "default VPC"
+		}
+	case *ec2.Subnet:
+		if r.DefaultForAz != nil && *r.DefaultForAz {
+			return "default subnet"
+		}
+	case *ec2.SecurityGroup:
+		if r.GroupName != nil && *r.GroupName == "default" {
+			return "default security group"
+		}
+	case *ec2.RouteTable:
+		for _, a := range r.Associations {
+			if a.Main != nil && *a.Main {
+				return "main route table"
+			}
+		}
+	case *ec2.NetworkAcl:
+		if r.IsDefault != nil && *r.IsDefault {
+			return "default network ACL"
+		}
+	case *ec2.DhcpOptions:
+		if isDefaultDhcpOptions(r) {
+			return "default DHCP options"
+		}
+	}
+	return ""
+}
+
+// isDefaultDhcpOptions tells if a DHCP options set is the one created by AWS, which
+// only sets the region's internal domain name and the Amazon provided DNS server.
+func isDefaultDhcpOptions(o *ec2.DhcpOptions) bool {
+	if len(o.DhcpConfigurations) != 2 {
+		return false
+	}
+
+	for _, cfg := range o.DhcpConfigurations {
+		if len(cfg.Values) != 1 || cfg.Values[0].Value == nil {
+			return false
+		}
+		value := *cfg.Values[0].Value
+
+		switch *cfg.Key {
+		case "domain-name":
+			// ec2.internal in us-east-1, <region>.compute.internal elsewhere
+			if value != "ec2.internal" && !strings.HasSuffix(value, ".compute.internal") {
+				return false
+			}
+		case "domain-name-servers":
+			if value != "AmazonProvidedDNS" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 func getTags(res reflect.Value) *map[string]string {
@@ -195,6 +692,29 @@ func getTerraformTypes(aMap map[string]yamlCfg) []string {
 	return ttypes
 }
 func (c *WipeCommand) inCfg(rType string, id *string, tags ...*map[string]string) bool {
+	key := rType + "/" + *id
+	if match, ok := c.matches[key]; ok {
+		return match
+	}
+	match := c.matchesFilters(rType, id, tags...)
+	c.matches[key] = match
+	return match
+}
+
+func (c *WipeCommand) matchesFilters(rType string, id *string, tags ...*map[string]string) bool {
+	if !c.matchesIdsOrTags(rType, id, tags...) {
+		return false
+	}
+	if len(c.deleteCfg[rType].CreatedBy) > 0 && !c.isCreatedBy(rType, id) {
+		return false
+	}
+	if c.deleteCfg[rType].Idle != nil {
+		return c.isIdle(rType, id)
+	}
+	return true
+}
+
+func (c *WipeCommand) matchesIdsOrTags(rType string, id *string, tags ...*map[string]string) bool {
 	if cfgVal, ok := c.deleteCfg[rType]; ok {
 		if len(cfgVal.Ids) == 0 && len(cfgVal.Tags) == 0 {
 			return true
@@ -218,6 +738,588 @@ func (c *WipeCommand) inCfg(rType string, id *string, tags ...*map[string]string
 	return false
 }
 
+// validateIdle checks the statistic and the metric of an idle filter, as a typo
+// would otherwise leave every resource without data points.
+func (c *WipeCommand) validateIdle(rType string) error {
+	cfg := c.deleteCfg[rType].Idle
+	m := idleMetrics[rType]
+
+	if cfg.Statistic != "" {
+		valid := false
+		for _, s := range []string{cloudwatch.StatisticAverage, cloudwatch.StatisticSum, cloudwatch.StatisticMaximum,
+			cloudwatch.StatisticMinimum, cloudwatch.StatisticSampleCount} {
+			if cfg.Statistic == s {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown statistic '%s'", cfg.Statistic)
+		}
+	}
+
+	if cfg.Metric != "" && cfg.Metric != m.metric {
+		// the default threshold only makes sense for the default metric
+		if cfg.Below == 0 {
+			return fmt.Errorf("'below' is required for metric '%s'", cfg.Metric)
+		}
+
+		out, err := c.client.cloudwatchconn.ListMetrics(&cloudwatch.ListMetricsInput{
+			Namespace:  aws.String(m.namespace),
+			MetricName: aws.String(cfg.Metric),
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Metrics) == 0 {
+			return fmt.Errorf("unknown metric '%s' in namespace %s", cfg.Metric, m.namespace)
+		}
+	}
+	return nil
+}
+
+// isIdle tells if a resource did nothing according to its CloudWatch metric. Resources
+// younger than the period of the filter are never idle, as their metric doesn't cover it.
+func (c *WipeCommand) isIdle(rType string, id *string) bool {
+	cfg := c.deleteCfg[rType].Idle
+	m := idleMetrics[rType]
+
+	if cfg.Metric != "" {
+		m.metric = cfg.Metric
+	}
+	if cfg.Statistic != "" {
+		m.statistic = cfg.Statistic
+	}
+	if cfg.Below != 0 {
+		m.below = cfg.Below
+	}
+	days := cfg.Days
+	if days == 0 {
+		days = 14
+	}
+
+	now := time.Now()
+	start := now.AddDate(0, 0, -days)
+
+	created, err := c.getCreateTime(rType, id)
+	if err != nil {
+		fmt.Printf("\t%s\n", err)
+		return false
+	}
+	if created == nil || created.After(start) {
+		return false
+	}
+
+	out, err := c.client.cloudwatchconn.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(m.namespace),
+		MetricName: aws.String(m.metric),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String(m.dimension),
+				Value: id,
+			},
+		},
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int64(24 * 60 * 60),
+		Statistics: aws.StringSlice([]string{m.statistic}),
+	})
+	if err != nil {
+		fmt.Printf("\t%s\n", err)
+		return false
+	}
+
+	// no data points, e.g. for stopped instances or ELBs without requests
+	if len(out.Datapoints) == 0 {
+		return true
+	}
+
+	// daily data points are combined according to the statistic
+	value := 0.0
+	for i, dp := range out.Datapoints {
+		switch m.statistic {
+		case cloudwatch.StatisticAverage:
+			value += *dp.Average / float64(len(out.Datapoints))
+		case cloudwatch.StatisticSum:
+			value += *dp.Sum
+		case cloudwatch.StatisticMaximum:
+			value = math.Max(value, *dp.Maximum)
+		case cloudwatch.StatisticMinimum:
+			if i == 0 {
+				value = *dp.Minimum
+			}
+			value = math.Min(value, *dp.Minimum)
+		case cloudwatch.StatisticSampleCount:
+			value += *dp.SampleCount
+		}
+	}
+	return value < m.below
+}
+
+// getCreateTime returns when a resource supporting the idle filter was created (or an instance last started).
+func (c *WipeCommand) getCreateTime(rType string, id *string) (*time.Time, error) {
+	switch rType {
+	case "aws_instance":
+		out, err := c.client.ec2conn.DescribeInstances(&ec2.DescribeInstancesInput{
+			InstanceIds: []*string{id},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range out.Reservations {
+			for _, in := range r.Instances {
+				return in.LaunchTime, nil
+			}
+		}
+	case "aws_elb":
+		out, err := c.client.elbconn.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+			LoadBalancerNames: []*string{id},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, lb := range out.LoadBalancerDescriptions {
+			return lb.CreatedTime, nil
+		}
+	case "aws_db_instance":
+		out, err := c.client.rdsconn.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, db := range out.DBInstances {
+			return db.InstanceCreateTime, nil
+		}
+	case "aws_nat_gateway":
+		out, err := c.client.ec2conn.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+			NatGatewayIds: []*string{id},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, nat := range out.NatGateways {
+			return nat.CreateTime, nil
+		}
+	}
+	return nil, nil
+}
+
+// inNames checks if the name of a resource matches the names filter of its type.
+// All names match if no filter is given.
+func (c *WipeCommand) inNames(rType string, name *string) bool {
+	regexes := c.deleteCfg[rType].Names
+	if len(regexes) == 0 {
+		return true
+	}
+	if name == nil {
+		return false
+	}
+
+	for _, regex := range regexes {
+		if ok, _ := regexp.MatchString(*regex, *name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inRegions checks if a region matches the regions filter of a resource type.
+// All regions match if no filter is given.
+func (c *WipeCommand) inRegions(rType string, region *string) bool {
+	regexes := c.deleteCfg[rType].Regions
+	if len(regexes) == 0 {
+		return true
+	}
+	if region == nil {
+		return false
+	}
+
+	for _, regex := range regexes {
+		if ok, _ := regexp.MatchString(*regex, *region); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IAM resources that are left untouched unless include_protected is set
+var (
+	protectedIamPaths = []string{"/aws-service-role/"}
+	protectedIamNames = []string{"OrganizationAccountAccessRole"}
+)
+
+// isProtected returns why an IAM resource is protected from deletion,
+// or an empty string if it isn't.
+func (c *WipeCommand) isProtected(rType string, path *string, name *string) string {
+	cfg := c.deleteCfg[rType]
+	if cfg.IncludeProtected {
+		return ""
+	}
+
+	for _, n := range protectedIamNames {
+		if name != nil && *name == n {
+			return "name " + n
+		}
+	}
+
+	paths := protectedIamPaths
+	for _, p := range cfg.ProtectedPaths {
+		paths = append(paths, *p)
+	}
+	for _, p := range paths {
+		if path != nil && strings.HasPrefix(*path, p) {
+			return "path " + p
+		}
+	}
+	return ""
+}
+
+// resource types supporting the last_used_before filter
+var lastUsedTypes = map[string]bool{
+	"aws_iam_user":       true,
+	"aws_iam_role":       true,
+	"aws_iam_access_key": true,
+}
+
+// parseAge parses a duration like time.ParseDuration, with days (e.g. 90d) in addition.
+func parseAge(age string) (time.Duration, error) {
+	if strings.HasSuffix(age, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(age, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(age)
+}
+
+// isUnused tells if an IAM principal wasn't used since last_used_before. lastUsed is only
+// called if the filter is set, as it may need further API calls. Principals which have never
+// been used only match with never_used, and if they were created before as well.
+func (c *WipeCommand) isUnused(rType string, id *string, created *time.Time, lastUsed func() ([]*time.Time, error)) bool {
+	cfg := c.deleteCfg[rType]
+	if cfg.LastUsedBefore == "" {
+		return true
+	}
+
+	key := rType + "/" + *id
+	if unused, ok := c.unused[key]; ok {
+		return unused
+	}
+	unused, err := isUnusedSince(cfg, c.now, created, lastUsed)
+	if err != nil {
+		// resources whose last use can't be established never match
+		fmt.Printf("Err: Last use of %s '%s' unknown, skipped:\n\t%s\n", rType, *id, err)
+	}
+	c.unused[key] = unused
+	return unused
+}
+
+func isUnusedSince(cfg yamlCfg, now time.Time, created *time.Time, lastUsed func() ([]*time.Time, error)) (bool, error) {
+	age, err := parseAge(cfg.LastUsedBefore)
+	check(err)
+	before := now.Add(-age)
+
+	used, err := lastUsed()
+	if err != nil {
+		return false, err
+	}
+
+	var latest *time.Time
+	for _, t := range used {
+		if t != nil && (latest == nil || t.After(*latest)) {
+			latest = t
+		}
+	}
+
+	if latest == nil {
+		return cfg.NeverUsed && created != nil && created.Before(before), nil
+	}
+	return !latest.After(before), nil
+}
+
+// CloudTrail only keeps events of the last 90 days
+const cloudTrailRetention = 90 * 24 * time.Hour
+
+// getRoleLastUsed returns when a role was last assumed according to CloudTrail, as the vendored
+// SDK doesn't know RoleLastUsed yet. A role which wasn't assumed within the retention of CloudTrail
+// was last used before it, unless it was created later.
+func (c *WipeCommand) getRoleLastUsed(role *iam.Role) ([]*time.Time, error) {
+	trails, err := c.getRoleTrails()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *time.Time
+	for _, trail := range trails {
+		input := &cloudtrail.LookupEventsInput{
+			LookupAttributes: []*cloudtrail.LookupAttribute{
+				{
+					AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+					AttributeValue: role.Arn,
+				},
+			},
+		}
+	pages:
+		for {
+			out, err := c.lookupEvents(trail, input)
+			if err != nil {
+				return nil, err
+			}
+			// events are returned newest first
+			for _, e := range out.Events {
+				// AssumeRole, AssumeRoleWithSAML and AssumeRoleWithWebIdentity
+				if strings.HasPrefix(*e.EventName, "AssumeRole") {
+					if latest == nil || e.EventTime.After(*latest) {
+						latest = e.EventTime
+					}
+					break pages
+				}
+			}
+
+			if out.NextToken == nil {
+				break
+			}
+			input.NextToken = out.NextToken
+		}
+	}
+	if latest != nil {
+		return []*time.Time{latest}, nil
+	}
+
+	retention := c.now.Add(-cloudTrailRetention)
+	if role.CreateDate != nil && role.CreateDate.Before(retention) {
+		return []*time.Time{&retention}, nil
+	}
+	return nil, nil
+}
+
+// getRoleTrails returns the CloudTrail clients of all regions enabled for the account. Roles are
+// global, and each region logs the calls to its STS endpoint, while us-east-1 logs those to the
+// global endpoint.
+func (c *WipeCommand) getRoleTrails() ([]*cloudtrail.CloudTrail, error) {
+	if c.roleTrails != nil {
+		return c.roleTrails, nil
+	}
+
+	out, err := c.client.ec2conn.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	trails := []*cloudtrail.CloudTrail{}
+	for _, r := range out.Regions {
+		if *r.RegionName == aws.StringValue(c.client.cloudtrailconn.Config.Region) {
+			trails = append(trails, c.client.cloudtrailconn)
+			continue
+		}
+		trails = append(trails, cloudtrail.New(c.client.sess, aws.NewConfig().WithRegion(*r.RegionName)))
+	}
+	c.roleTrails = trails
+	return trails, nil
+}
+
+// getAccessKeyLastUsed returns when an access key was last used, if ever.
+func (c *WipeCommand) getAccessKeyLastUsed(id *string) *time.Time {
+	out, err := c.client.iamconn.GetAccessKeyLastUsed(&iam.GetAccessKeyLastUsedInput{
+		AccessKeyId: id,
+	})
+	check(err)
+
+	if out.AccessKeyLastUsed == nil {
+		return nil
+	}
+	return out.AccessKeyLastUsed.LastUsedDate
+}
+
+// CloudTrail allows two lookups per second per account and region
+const cloudTrailRateLimit = 500 * time.Millisecond
+
+// createEvent is a CloudTrail event creating a resource. The ID of the created resource is part
+// of the response, or of the request parameter param for events whose response doesn't contain it.
+type createEvent struct {
+	name  string
+	param string
+}
+
+// events creating the resource types supporting the created_by filter
+var createEvents = map[string][]createEvent{
+	"aws_instance":             {{"RunInstances", ""}},
+	"aws_security_group":       {{"CreateSecurityGroup", ""}},
+	"aws_ebs_volume":           {{"CreateVolume", ""}},
+	"aws_ebs_snapshot":         {{"CreateSnapshot", ""}, {"CopySnapshot", ""}},
+	"aws_ami":                  {{"CreateImage", ""}, {"RegisterImage", ""}, {"CopyImage", ""}},
+	"aws_eip":                  {{"AllocateAddress", ""}},
+	"aws_key_pair":             {{"CreateKeyPair", ""}, {"ImportKeyPair", ""}},
+	"aws_vpc":                  {{"CreateVpc", ""}},
+	"aws_subnet":               {{"CreateSubnet", ""}},
+	"aws_network_interface":    {{"CreateNetworkInterface", ""}},
+	"aws_nat_gateway":          {{"CreateNatGateway", ""}},
+	"aws_internet_gateway":     {{"CreateInternetGateway", ""}},
+	"aws_route_table":          {{"CreateRouteTable", ""}},
+	"aws_autoscaling_group":    {{"CreateAutoScalingGroup", "autoScalingGroupName"}},
+	"aws_launch_configuration": {{"CreateLaunchConfiguration", "launchConfigurationName"}},
+	"aws_elb":                  {{"CreateLoadBalancer", "loadBalancerName"}},
+	"aws_db_instance":          {{"CreateDBInstance", ""}},
+	"aws_s3_bucket":            {{"CreateBucket", "bucketName"}},
+	"aws_iam_user":             {{"CreateUser", ""}},
+	"aws_iam_role":             {{"CreateRole", ""}},
+	"aws_iam_policy":           {{"CreatePolicy", ""}},
+}
+
+func (c *WipeCommand) isCreatedBy(rType string, id *string) bool {
+	creator := c.getCreator(rType, id)
+	if creator == "" {
+		return false
+	}
+	for _, regex := range c.deleteCfg[rType].CreatedBy {
+		if ok, _ := regexp.MatchString(*regex, creator); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getCreator returns the ARN of the principal in the create event of a resource,
+// or an empty string if there is none. CloudTrail only keeps events of the last 90 days.
+func (c *WipeCommand) getCreator(rType string, id *string) string {
+	if c.creators == nil {
+		c.loadCreators()
+	}
+	if creator, ok := c.creators[*id]; ok {
+		return creator
+	}
+
+	creator := ""
+	var createdAt time.Time
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{
+				AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+				AttributeValue: id,
+			},
+		},
+	}
+	for {
+		out, err := c.lookupEvents(c.client.cloudtrailconn, input)
+		check(err)
+		for _, e := range out.Events {
+			if creator != "" && !e.EventTime.Before(createdAt) {
+				continue
+			}
+			if arn := getCreateEventPrincipal(rType, id, e); arn != "" {
+				creator = arn
+				createdAt = *e.EventTime
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	// unknown creators aren't cached, as events are delivered with a delay
+	if creator != "" {
+		c.creators[*id] = creator
+	}
+	return creator
+}
+
+// lookupEvents looks up CloudTrail events within the rate limit of the trail's region.
+func (c *WipeCommand) lookupEvents(trail *cloudtrail.CloudTrail, input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error) {
+	region := aws.StringValue(trail.Config.Region)
+	time.Sleep(cloudTrailRateLimit - time.Since(c.lastLookup[region]))
+	out, err := trail.LookupEvents(input)
+	c.lastLookup[region] = time.Now()
+	return out, err
+}
+
+// getCreateEventPrincipal returns the ARN of the principal in an event if it created the resource,
+// or an empty string otherwise. Events found by resource name also include the ones that only refer
+// to the resource, e.g. the instances launched into a security group.
+func getCreateEventPrincipal(rType string, id *string, e *cloudtrail.Event) string {
+	for _, ce := range createEvents[rType] {
+		if *e.EventName != ce.name {
+			continue
+		}
+
+		var event struct {
+			UserIdentity struct {
+				Arn string `json:"arn"`
+			} `json:"userIdentity"`
+			RequestParameters map[string]interface{} `json:"requestParameters"`
+			ResponseElements  interface{}            `json:"responseElements"`
+		}
+		if err := json.Unmarshal([]byte(*e.CloudTrailEvent), &event); err != nil {
+			return ""
+		}
+
+		created := false
+		if ce.param != "" {
+			created = event.RequestParameters[ce.param] == *id
+		} else {
+			created = containsValue(event.ResponseElements, *id)
+		}
+		if created {
+			return event.UserIdentity.Arn
+		}
+	}
+	return ""
+}
+
+// containsValue tells if a value decoded from JSON contains the string s at any depth.
+func containsValue(v interface{}, s string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == s
+	case []interface{}:
+		for _, e := range v {
+			if containsValue(e, s) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			if containsValue(e, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadCreators loads the cache of creators for the current account and region,
+// so that repeated runs don't query CloudTrail again.
+func (c *WipeCommand) loadCreators() {
+	c.creators = map[string]string{}
+	c.creatorsFile = filepath.Join(os.Getenv("HOME"), ".awsweeper",
+		fmt.Sprintf("created_by-%s-%s.json", *c.getAccountId(), *c.client.ec2conn.Config.Region))
+
+	data, err := ioutil.ReadFile(c.creatorsFile)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &c.creators); err != nil {
+		fmt.Printf("\tIgnoring cache of creators: %s\n", err)
+		c.creators = map[string]string{}
+	}
+}
+
+func (c *WipeCommand) saveCreators() {
+	file := c.creatorsFile
+	data, err := json.MarshalIndent(c.creators, "", "  ")
+	check(err)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		fmt.Printf("\t%s\n", err)
+		return
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		fmt.Printf("\t%s\n", err)
+	}
+}
+
 func (c *WipeCommand) wipe(res Resources) {
 	numWorkerThreads := 10
 
@@ -225,7 +1327,15 @@ func (c *WipeCommand) wipe(res Resources) {
 		return
 	}
 
-	c.deleteOut[res.ttype] = yamlCfg{Ids: res.ids}
+	c.counts[res.ttype] += len(res.ids)
+	if c.counting {
+		return
+	}
+
+	// types can be wiped in several batches (e.g. the dependents of each VPC)
+	out := c.deleteOut[res.ttype]
+	out.Ids = append(out.Ids, res.ids...)
+	c.deleteOut[res.ttype] = out
 
 	fmt.Printf("\n---\nType: %s\nFound: %d\n\n", res.ttype, len(res.ids))
 
@@ -250,8 +1360,33 @@ func (c *WipeCommand) wipe(res Resources) {
 	if len(res.tags) > 0 {
 		ts = res.tags
 	}
+
+	ds := make([]*map[string]string, len(res.ids))
+	if len(res.details) > 0 {
+		ds = res.details
+	}
+
+	for i, id := range res.ids {
+		if creator, ok := c.creators[*id]; ok {
+			details := map[string]string{"created_by": creator}
+			if ds[i] != nil {
+				for k, v := range *ds[i] {
+					details[k] = v
+				}
+			}
+			ds[i] = &details
+		}
+	}
+	deleteFn := res.deleteFn
 	chResources := make(chan *Resource, numWorkerThreads)
 
+	var limiter <-chan time.Time
+	if res.rateLimit > 0 {
+		ticker := time.NewTicker(res.rateLimit)
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(len(res.ids))
 
@@ -270,8 +1405,37 @@ func (c *WipeCommand) wipe(res Resources) {
 						}
 						printStat += "\n"
 					}
+					if res.details != nil && len(*res.details) > 0 {
+						if res.tags == nil {
+							printStat += "\n"
+						}
+						keys := []string{}
+						for k := range *res.details {
+							keys = append(keys, k)
+						}
+						sort.Strings(keys)
+
+						printStat += "\tInfo:\t"
+						for _, k := range keys {
+							printStat += fmt.Sprintf("[%s: %s] ", k, (*res.details)[k])
+						}
+						printStat += "\n"
+					}
 					fmt.Println(printStat)
 
+					if deleteFn != nil {
+						if !c.dryRun {
+							if limiter != nil {
+								<-limiter
+							}
+							if err := deleteFn(res.id); err != nil {
+								fmt.Printf("\t%s\n", err)
+							}
+						}
+						wg.Done()
+						continue
+					}
+
 					a := res.attrs
 					(*a)["force_destroy"] = "true"
 
@@ -287,7 +1451,21 @@ func (c *WipeCommand) wipe(res Resources) {
 						st.Attributes["force_destroy"] = "true"
 					}
 
+					// the resource is already gone (e.g. deleted together with another one)
+					if st == nil {
+						wg.Done()
+						continue
+					}
+
+					// attributes set for deletion take precedence over refreshed ones
+					for k, v := range *a {
+						st.Attributes[k] = v
+					}
+
 					if !c.dryRun {
+						if limiter != nil {
+							<-limiter
+						}
 						_, err = (*c.provider).Apply(ii, st, d)
 
 						if err != nil {
@@ -306,15 +1484,16 @@ func (c *WipeCommand) wipe(res Resources) {
 		if id != nil {
 			chResources <- &Resource{
 				id:    id,
-				attrs: a[i],
-				tags:  ts[i],
+				attrs:   a[i],
+				tags:    ts[i],
+				details: ds[i],
 			}
 		}
 	}
 	close(chResources)
 
 	wg.Wait()
-	fmt.Println("---\n")
+	fmt.Print("---\n\n")
 }
 
 func check(e error) {
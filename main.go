@@ -21,6 +21,36 @@ import (
 	"flag"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/emr"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/aws-sdk-go/service/codecommit"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/codepipeline"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/glacier"
+	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/waf"
+	"github.com/aws/aws-sdk-go/service/wafregional"
+	"github.com/aws/aws-sdk-go/service/opsworks"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/directoryservice"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
 )
 
 func main() {
@@ -38,6 +68,8 @@ func main() {
 	profile := flag.String("profile", "", "Use a specific profile from your credential file")
 	region := flag.String("region", "", "The region to use. Overrides config/env settings")
 	outFileName := flag.String("output", "", "List deleted resources in yaml file")
+	maxDelete := flag.Int("max-delete", 0, "Abort if more resources would be deleted in total")
+	maxDeletePercent := flag.Int("max-delete-percent", 0, "Abort if more percent of the resources of a type would be deleted")
 
 	flag.Usage = func() { fmt.Println(Help()) }
 	flag.Parse()
@@ -57,7 +89,11 @@ func main() {
 		Version: version,
 		HelpFunc: BasicHelpFunc(app),
 	}
-	c.Args = append([]string{"wipe"}, flag.Args()...)
+	if flag.Arg(0) == "orphans" {
+		c.Args = flag.Args()
+	} else {
+		c.Args = append([]string{"wipe"}, flag.Args()...)
+	}
 
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
@@ -87,6 +123,37 @@ func main() {
 		kmsconn: kms.New(sess),
 		s3conn: s3.New(sess),
 		stsconn: sts.New(sess),
+		esconn: elasticsearchservice.New(sess),
+		redshiftconn: redshift.New(sess),
+		emrconn: emr.New(sess),
+		acmconn: acm.New(sess),
+		cloudfrontconn: cloudfront.New(sess),
+		apigatewayconn: apigateway.New(sess),
+		kinesisconn: kinesis.New(sess),
+		firehoseconn: firehose.New(sess),
+		sfnconn: sfn.New(sess),
+		codebuildconn: codebuild.New(sess),
+		codecommitconn: codecommit.New(sess),
+		codedeployconn: codedeploy.New(sess),
+		codepipelineconn: codepipeline.New(sess),
+		ssmconn: ssm.New(sess),
+		beanstalkconn: elasticbeanstalk.New(sess),
+		glacierconn: glacier.New(sess),
+		simpledbconn: simpledb.New(sess),
+		sesconn: ses.New(sess),
+		wafconn: waf.New(sess),
+		wafregionalconn: wafregional.New(sess),
+		opsworksconn: opsworks.New(sess),
+		cognitoconn: cognitoidentity.New(sess),
+		dsconn: directoryservice.New(sess),
+		lightsailconn: lightsail.New(sess),
+		dmsconn: databasemigrationservice.New(sess),
+		lambdaconn: lambda.New(sess),
+		rdsconn: rds.New(sess),
+		elbv2conn: elbv2.New(sess),
+		cloudwatchconn: cloudwatch.New(sess),
+		cloudtrailconn: cloudtrail.New(sess),
+		sess: sess,
 	}
 
 	c.Commands = map[string]cli.CommandFactory{
@@ -101,6 +168,18 @@ func main() {
 				dryRun: *dryRunFlag,
 				forceDelete: *forceDeleteFlag,
 				outFileName: *outFileName,
+				maxDelete: *maxDelete,
+				maxDeletePercent: *maxDeletePercent,
+			}, nil
+		},
+		"orphans": func() (cli.Command, error) {
+			return &OrphansCommand{
+				Ui: &cli.ColoredUi{
+					Ui:          ui,
+					OutputColor: cli.UiColorBlue,
+				},
+				client: client,
+				outFileName: *outFileName,
 			}, nil
 		},
 	}
@@ -115,9 +194,13 @@ func main() {
 
 func Help() string {
 	return `Usage: awsweeper [options] <config.yaml>
+       awsweeper [options] orphans
 
   Delete AWS resources via a yaml configuration.
 
+  The orphans command prints a yaml configuration of unused resources
+  (e.g. unattached volumes), which can be reviewed and passed to awsweeper.
+
 Options:
   --profile		Use a specific profile from your credential file
 
@@ -128,6 +211,13 @@ Options:
   --force		Start deleting without asking for confirmation
 
   --output=file		Print infos about deleted resources to a yaml file
+			(or the configuration of unused resources for orphans)
+
+  --max-delete=n	Abort before deleting anything if more than n resources
+			would be deleted in total
+
+  --max-delete-percent=p	Abort before deleting anything if more than p percent
+			of the existing resources of a type would be deleted
 `
 }
 
@@ -10,9 +10,41 @@ import (
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/emr"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/aws/aws-sdk-go/service/codecommit"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/codepipeline"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/glacier"
+	"github.com/aws/aws-sdk-go/service/simpledb"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/waf"
+	"github.com/aws/aws-sdk-go/service/opsworks"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/directoryservice"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"github.com/aws/aws-sdk-go/service/rds"
 )
 
 func getResourceInfos(c *WipeCommand) []ResourceInfo {
+	// AWS managed policies can't be deleted, so they are only listed on explicit request
+	policyScope := iam.PolicyScopeTypeLocal
+	if c.deleteCfg["aws_iam_policy"].IncludeProtected {
+		policyScope = iam.PolicyScopeTypeAll
+	}
+
 	return []ResourceInfo{
 		{
 			"aws_autoscaling_group",
@@ -30,6 +62,29 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&autoscaling.DescribeLaunchConfigurationsInput{},
 			c.deleteGeneric,
 		},
+		{
+			"aws_spot_fleet_request",
+			"SpotFleetRequestConfigs",
+			"SpotFleetRequestId",
+			c.client.ec2conn.DescribeSpotFleetRequests,
+			&ec2.DescribeSpotFleetRequestsInput{},
+			c.deleteSpotFleetRequests,
+		},
+		{
+			"aws_spot_instance_request",
+			"SpotInstanceRequests",
+			"SpotInstanceRequestId",
+			c.client.ec2conn.DescribeSpotInstanceRequests,
+			&ec2.DescribeSpotInstanceRequestsInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("state"),
+						Values: aws.StringSlice([]string{"open", "active"}),
+					},
+				},
+			},
+			c.deleteGeneric,
+		},
 		{
 			"aws_instance",
 			"Reservations",
@@ -38,6 +93,29 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&ec2.DescribeInstancesInput{},
 			c.deleteInstances,
 		},
+		{
+			"aws_key_pair",
+			"KeyPairs",
+			"KeyName",
+			c.client.ec2conn.DescribeKeyPairs,
+			&ec2.DescribeKeyPairsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_placement_group",
+			"PlacementGroups",
+			"GroupName",
+			c.client.ec2conn.DescribePlacementGroups,
+			&ec2.DescribePlacementGroupsInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("state"),
+						Values: aws.StringSlice([]string{"pending", "available"}),
+					},
+				},
+			},
+			c.deleteGeneric,
+		},
 		{
 			"aws_elb",
 			"LoadBalancerDescriptions",
@@ -46,6 +124,14 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&elb.DescribeLoadBalancersInput{},
 			c.deleteGeneric,
 		},
+		{
+			"aws_db_instance",
+			"DBInstances",
+			"DBInstanceIdentifier",
+			c.client.rdsconn.DescribeDBInstances,
+			&rds.DescribeDBInstancesInput{},
+			c.deleteDbInstances,
+		},
 		{
 			"aws_vpc_endpoint",
 			"VpcEndpoints",
@@ -71,6 +157,62 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&cloudformation.DescribeStacksInput{},
 			c.deleteGeneric,
 		},
+		{
+			"aws_api_gateway_domain_name",
+			"Items",
+			"DomainName",
+			c.client.apigatewayconn.GetDomainNames,
+			&apigateway.GetDomainNamesInput{
+				Limit: aws.Int64(500),
+			},
+			c.deleteApiGatewayDomainNames,
+		},
+		{
+			"aws_api_gateway_usage_plan",
+			"Items",
+			"Id",
+			c.client.apigatewayconn.GetUsagePlans,
+			&apigateway.GetUsagePlansInput{
+				Limit: aws.Int64(500),
+			},
+			c.deleteApiGatewayUsagePlans,
+		},
+		{
+			"aws_api_gateway_api_key",
+			"Items",
+			"Id",
+			c.client.apigatewayconn.GetApiKeys,
+			&apigateway.GetApiKeysInput{
+				Limit: aws.Int64(500),
+			},
+			c.deleteApiGatewayApiKeys,
+		},
+		{
+			"aws_api_gateway_rest_api",
+			"Items",
+			"Id",
+			c.client.apigatewayconn.GetRestApis,
+			&apigateway.GetRestApisInput{
+				Limit: aws.Int64(500),
+			},
+			c.deleteApiGatewayRestApis,
+		},
+		{
+			"aws_cloudfront_distribution",
+			"DistributionList",
+			"Id",
+			c.client.cloudfrontconn.ListDistributions,
+			&cloudfront.ListDistributionsInput{},
+			c.deleteCloudFrontDistributions,
+		},
+		{
+			"aws_cloudfront_origin_access_identity",
+			"CloudFrontOriginAccessIdentityList",
+			"Id",
+			c.client.cloudfrontconn.ListCloudFrontOriginAccessIdentities,
+			&cloudfront.ListCloudFrontOriginAccessIdentitiesInput{},
+			c.deleteGeneric,
+		},
 		{
 			"aws_route53_zone",
 			"HostedZones",
@@ -123,6 +265,82 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&ec2.DescribeInternetGatewaysInput{},
 			c.deleteInternetGateways,
 		},
+		{
+			"aws_egress_only_internet_gateway",
+			"EgressOnlyInternetGateways",
+			"EgressOnlyInternetGatewayId",
+			c.client.ec2conn.DescribeEgressOnlyInternetGateways,
+			&ec2.DescribeEgressOnlyInternetGatewaysInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_vpn_connection",
+			"VpnConnections",
+			"VpnConnectionId",
+			c.client.ec2conn.DescribeVpnConnections,
+			&ec2.DescribeVpnConnectionsInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("state"),
+						Values: aws.StringSlice([]string{"pending", "available"}),
+					},
+				},
+			},
+			c.deleteGeneric,
+		},
+		{
+			"aws_vpn_gateway",
+			"VpnGateways",
+			"VpnGatewayId",
+			c.client.ec2conn.DescribeVpnGateways,
+			&ec2.DescribeVpnGatewaysInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("state"),
+						Values: aws.StringSlice([]string{"pending", "available"}),
+					},
+				},
+			},
+			c.deleteVpnGateways,
+		},
+		{
+			"aws_customer_gateway",
+			"CustomerGateways",
+			"CustomerGatewayId",
+			c.client.ec2conn.DescribeCustomerGateways,
+			&ec2.DescribeCustomerGatewaysInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("state"),
+						Values: aws.StringSlice([]string{"pending", "available"}),
+					},
+				},
+			},
+			c.deleteGeneric,
+		},
+		{
+			"aws_vpc_peering_connection",
+			"VpcPeeringConnections",
+			"VpcPeeringConnectionId",
+			c.client.ec2conn.DescribeVpcPeeringConnections,
+			&ec2.DescribeVpcPeeringConnectionsInput{
+				Filters: []*ec2.Filter{
+					{
+						Name:   aws.String("status-code"),
+						Values: aws.StringSlice([]string{"initiating-request", "pending-acceptance", "provisioning", "active"}),
+					},
+				},
+			},
+			c.deleteGeneric,
+		},
+		{
+			"aws_flow_log",
+			"FlowLogs",
+			"FlowLogId",
+			c.client.ec2conn.DescribeFlowLogs,
+			&ec2.DescribeFlowLogsInput{},
+			c.deleteGeneric,
+		},
 		{
 			"aws_subnet",
 			"Subnets",
@@ -145,7 +363,7 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			"GroupId",
 			c.client.ec2conn.DescribeSecurityGroups,
 			&ec2.DescribeSecurityGroupsInput{},
-			c.deleteGeneric,
+			c.deleteSecurityGroups,
 		},
 		{
 			"aws_network_acl",
@@ -161,6 +379,14 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			"VpcId",
 			c.client.ec2conn.DescribeVpcs,
 			&ec2.DescribeVpcsInput{},
+			c.deleteVpcs,
+		},
+		{
+			"aws_vpc_dhcp_options",
+			"DhcpOptions",
+			"DhcpOptionsId",
+			c.client.ec2conn.DescribeDhcpOptions,
+			&ec2.DescribeDhcpOptionsInput{},
 			c.deleteGeneric,
 		},
 		{
@@ -168,7 +394,9 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			"Policies",
 			"Arn",
 			c.client.iamconn.ListPolicies,
-			&iam.ListPoliciesInput{},
+			&iam.ListPoliciesInput{
+				Scope: aws.String(policyScope),
+			},
 			c.deleteIamPolicy,
 		},
 		{
@@ -177,7 +405,15 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			"GroupName",
 			c.client.iamconn.ListGroups,
 			&iam.ListGroupsInput{},
-			c.deleteGeneric,
+			c.deleteIamGroups,
+		},
+		{
+			"aws_iam_access_key",
+			"Users",
+			"UserName",
+			c.client.iamconn.ListUsers,
+			&iam.ListUsersInput{},
+			c.deleteIamAccessKeys,
 		},
 		{
 			"aws_iam_user",
@@ -203,6 +439,38 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&iam.ListInstanceProfilesInput{},
 			c.deleteInstanceProfiles,
 		},
+		{
+			"aws_iam_server_certificate",
+			"ServerCertificateMetadataList",
+			"ServerCertificateName",
+			c.client.iamconn.ListServerCertificates,
+			&iam.ListServerCertificatesInput{},
+			c.deleteIamServerCertificates,
+		},
+		{
+			"aws_iam_saml_provider",
+			"SAMLProviderList",
+			"Arn",
+			c.client.iamconn.ListSAMLProviders,
+			&iam.ListSAMLProvidersInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_iam_openid_connect_provider",
+			"OpenIDConnectProviderList",
+			"Arn",
+			c.client.iamconn.ListOpenIDConnectProviders,
+			&iam.ListOpenIDConnectProvidersInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_acm_certificate",
+			"CertificateSummaryList",
+			"CertificateArn",
+			c.client.acmconn.ListCertificates,
+			&acm.ListCertificatesInput{},
+			c.deleteAcmCertificates,
+		},
 		{
 			"aws_kms_alias",
 			"Aliases",
@@ -251,5 +519,409 @@ func getResourceInfos(c *WipeCommand) []ResourceInfo {
 			&ec2.DescribeImagesInput{},
 			c.deleteAmis,
 		},
+		{
+			"aws_elasticsearch_domain",
+			"DomainNames",
+			"DomainName",
+			c.client.esconn.ListDomainNames,
+			&elasticsearchservice.ListDomainNamesInput{},
+			c.deleteElasticsearchDomains,
+		},
+		{
+			"aws_redshift_cluster",
+			"Clusters",
+			"ClusterIdentifier",
+			c.client.redshiftconn.DescribeClusters,
+			&redshift.DescribeClustersInput{},
+			c.deleteRedshiftClusters,
+		},
+		{
+			"aws_redshift_subnet_group",
+			"ClusterSubnetGroups",
+			"ClusterSubnetGroupName",
+			c.client.redshiftconn.DescribeClusterSubnetGroups,
+			&redshift.DescribeClusterSubnetGroupsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_emr_cluster",
+			"Clusters",
+			"Id",
+			c.client.emrconn.ListClusters,
+			&emr.ListClustersInput{
+				ClusterStates: aws.StringSlice([]string{
+					emr.ClusterStateStarting,
+					emr.ClusterStateBootstrapping,
+					emr.ClusterStateRunning,
+					emr.ClusterStateWaiting,
+				}),
+			},
+			c.deleteEmrClusters,
+		},
+		{
+			"aws_kinesis_firehose_delivery_stream",
+			"DeliveryStreamNames",
+			"DeliveryStreamName",
+			c.client.firehoseconn.ListDeliveryStreams,
+			&firehose.ListDeliveryStreamsInput{
+				Limit: aws.Int64(1000),
+			},
+			c.deleteFirehoseDeliveryStreams,
+		},
+		{
+			"aws_kinesis_stream",
+			"StreamNames",
+			"StreamName",
+			c.client.kinesisconn.ListStreams,
+			&kinesis.ListStreamsInput{
+				Limit: aws.Int64(1000),
+			},
+			c.deleteKinesisStreams,
+		},
+		{
+			"aws_sfn_state_machine",
+			"StateMachines",
+			"StateMachineArn",
+			c.client.sfnconn.ListStateMachines,
+			&sfn.ListStateMachinesInput{},
+			c.deleteSfnStateMachines,
+		},
+		{
+			"aws_sfn_activity",
+			"Activities",
+			"ActivityArn",
+			c.client.sfnconn.ListActivities,
+			&sfn.ListActivitiesInput{},
+			c.deleteSfnActivities,
+		},
+		{
+			"aws_codepipeline",
+			"Pipelines",
+			"Name",
+			c.client.codepipelineconn.ListPipelines,
+			&codepipeline.ListPipelinesInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_codebuild_project",
+			"Projects",
+			"Name",
+			c.client.codebuildconn.ListProjects,
+			&codebuild.ListProjectsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_codedeploy_deployment_group",
+			"Applications",
+			"ApplicationName",
+			c.client.codedeployconn.ListApplications,
+			&codedeploy.ListApplicationsInput{},
+			c.deleteCodeDeployDeploymentGroups,
+		},
+		{
+			"aws_codedeploy_app",
+			"Applications",
+			"ApplicationName",
+			c.client.codedeployconn.ListApplications,
+			&codedeploy.ListApplicationsInput{},
+			c.deleteCodeDeployApps,
+		},
+		{
+			"aws_codecommit_repository",
+			"Repositories",
+			"RepositoryName",
+			c.client.codecommitconn.ListRepositories,
+			&codecommit.ListRepositoriesInput{},
+			c.deleteCodeCommitRepositories,
+		},
+		{
+			"aws_ssm_association",
+			"Associations",
+			"AssociationId",
+			c.client.ssmconn.ListAssociations,
+			&ssm.ListAssociationsInput{},
+			c.deleteSsmAssociations,
+		},
+		{
+			"aws_ssm_maintenance_window",
+			"WindowIdentities",
+			"WindowId",
+			c.client.ssmconn.DescribeMaintenanceWindows,
+			&ssm.DescribeMaintenanceWindowsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_ssm_document",
+			"DocumentIdentifiers",
+			"Name",
+			c.client.ssmconn.ListDocuments,
+			&ssm.ListDocumentsInput{
+				DocumentFilterList: []*ssm.DocumentFilter{
+					{
+						Key:   aws.String(ssm.DocumentFilterKeyOwner),
+						Value: aws.String("Self"),
+					},
+				},
+			},
+			c.deleteSsmDocuments,
+		},
+		{
+			"aws_ssm_activation",
+			"ActivationList",
+			"ActivationId",
+			c.client.ssmconn.DescribeActivations,
+			&ssm.DescribeActivationsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_ssm_parameter",
+			"Parameters",
+			"Name",
+			c.client.ssmconn.DescribeParameters,
+			&ssm.DescribeParametersInput{
+				MaxResults: aws.Int64(50),
+			},
+			c.deleteSsmParameters,
+		},
+		{
+			"aws_elastic_beanstalk_environment",
+			"Environments",
+			"EnvironmentId",
+			c.client.beanstalkconn.DescribeEnvironments,
+			&elasticbeanstalk.DescribeEnvironmentsInput{
+				IncludeDeleted: aws.Bool(false),
+			},
+			c.deleteBeanstalkEnvironments,
+		},
+		{
+			"aws_elastic_beanstalk_application_version",
+			"ApplicationVersions",
+			"VersionLabel",
+			c.client.beanstalkconn.DescribeApplicationVersions,
+			&elasticbeanstalk.DescribeApplicationVersionsInput{},
+			c.deleteBeanstalkApplicationVersions,
+		},
+		{
+			"aws_elastic_beanstalk_application",
+			"Applications",
+			"ApplicationName",
+			c.client.beanstalkconn.DescribeApplications,
+			&elasticbeanstalk.DescribeApplicationsInput{},
+			c.deleteBeanstalkApplications,
+		},
+		{
+			"aws_glacier_vault",
+			"VaultList",
+			"VaultName",
+			c.client.glacierconn.ListVaults,
+			&glacier.ListVaultsInput{
+				AccountId: aws.String("-"),
+			},
+			c.deleteGlacierVaults,
+		},
+		{
+			"aws_simpledb_domain",
+			"DomainNames",
+			"DomainName",
+			c.client.simpledbconn.ListDomains,
+			&simpledb.ListDomainsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_ses_domain_identity",
+			"Identities",
+			"Identity",
+			c.client.sesconn.ListIdentities,
+			&ses.ListIdentitiesInput{
+				IdentityType: aws.String(ses.IdentityTypeDomain),
+			},
+			c.deleteSesDomainIdentities,
+		},
+		{
+			"aws_ses_receipt_rule_set",
+			"RuleSets",
+			"Name",
+			c.client.sesconn.ListReceiptRuleSets,
+			&ses.ListReceiptRuleSetsInput{},
+			c.deleteSesReceiptRuleSets,
+		},
+		{
+			"aws_ses_configuration_set",
+			"ConfigurationSets",
+			"Name",
+			c.client.sesconn.ListConfigurationSets,
+			&ses.ListConfigurationSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_waf_web_acl",
+			"WebACLs",
+			"WebACLId",
+			c.client.wafconn.ListWebACLs,
+			&waf.ListWebACLsInput{},
+			c.deleteWafWebAcls,
+		},
+		{
+			"aws_waf_rule",
+			"Rules",
+			"RuleId",
+			c.client.wafconn.ListRules,
+			&waf.ListRulesInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_waf_byte_match_set",
+			"ByteMatchSets",
+			"ByteMatchSetId",
+			c.client.wafconn.ListByteMatchSets,
+			&waf.ListByteMatchSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_waf_size_constraint_set",
+			"SizeConstraintSets",
+			"SizeConstraintSetId",
+			c.client.wafconn.ListSizeConstraintSets,
+			&waf.ListSizeConstraintSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_waf_sql_injection_match_set",
+			"SqlInjectionMatchSets",
+			"SqlInjectionMatchSetId",
+			c.client.wafconn.ListSqlInjectionMatchSets,
+			&waf.ListSqlInjectionMatchSetsInput{},
+			c.deleteWafSqlInjectionMatchSets,
+		},
+		{
+			"aws_waf_xss_match_set",
+			"XssMatchSets",
+			"XssMatchSetId",
+			c.client.wafconn.ListXssMatchSets,
+			&waf.ListXssMatchSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_waf_ipset",
+			"IPSets",
+			"IPSetId",
+			c.client.wafconn.ListIPSets,
+			&waf.ListIPSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_wafregional_byte_match_set",
+			"ByteMatchSets",
+			"ByteMatchSetId",
+			c.client.wafregionalconn.ListByteMatchSets,
+			&waf.ListByteMatchSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_wafregional_ipset",
+			"IPSets",
+			"IPSetId",
+			c.client.wafregionalconn.ListIPSets,
+			&waf.ListIPSetsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_opsworks_stack",
+			"Stacks",
+			"StackId",
+			c.client.opsworksconn.DescribeStacks,
+			&opsworks.DescribeStacksInput{},
+			c.deleteOpsWorksStacks,
+		},
+		{
+			"aws_cognito_identity_pool",
+			"IdentityPools",
+			"IdentityPoolId",
+			c.client.cognitoconn.ListIdentityPools,
+			&cognitoidentity.ListIdentityPoolsInput{
+				MaxResults: aws.Int64(60),
+			},
+			c.deleteCognitoIdentityPools,
+		},
+		{
+			"aws_directory_service_directory",
+			"DirectoryDescriptions",
+			"DirectoryId",
+			c.client.dsconn.DescribeDirectories,
+			&directoryservice.DescribeDirectoriesInput{},
+			c.deleteDirectories,
+		},
+		{
+			"aws_lightsail_instance",
+			"Instances",
+			"Name",
+			c.client.lightsailconn.GetInstances,
+			&lightsail.GetInstancesInput{},
+			c.deleteLightsailInstances,
+		},
+		{
+			"aws_lightsail_static_ip",
+			"StaticIps",
+			"Name",
+			c.client.lightsailconn.GetStaticIps,
+			&lightsail.GetStaticIpsInput{},
+			c.deleteLightsailStaticIps,
+		},
+		{
+			"aws_lightsail_key_pair",
+			"KeyPairs",
+			"Name",
+			c.client.lightsailconn.GetKeyPairs,
+			&lightsail.GetKeyPairsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_lightsail_domain",
+			"Domains",
+			"Name",
+			c.client.lightsailconn.GetDomains,
+			&lightsail.GetDomainsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_dms_replication_task",
+			"ReplicationTasks",
+			"ReplicationTaskIdentifier",
+			c.client.dmsconn.DescribeReplicationTasks,
+			&databasemigrationservice.DescribeReplicationTasksInput{},
+			c.deleteDmsReplicationTasks,
+		},
+		{
+			"aws_dms_replication_instance",
+			"ReplicationInstances",
+			"ReplicationInstanceIdentifier",
+			c.client.dmsconn.DescribeReplicationInstances,
+			&databasemigrationservice.DescribeReplicationInstancesInput{},
+			c.deleteDmsReplicationInstances,
+		},
+		{
+			"aws_dms_endpoint",
+			"Endpoints",
+			"EndpointIdentifier",
+			c.client.dmsconn.DescribeEndpoints,
+			&databasemigrationservice.DescribeEndpointsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_dms_replication_subnet_group",
+			"ReplicationSubnetGroups",
+			"ReplicationSubnetGroupIdentifier",
+			c.client.dmsconn.DescribeReplicationSubnetGroups,
+			&databasemigrationservice.DescribeReplicationSubnetGroupsInput{},
+			c.deleteGeneric,
+		},
+		{
+			"aws_dms_certificate",
+			"Certificates",
+			"CertificateIdentifier",
+			c.client.dmsconn.DescribeCertificates,
+			&databasemigrationservice.DescribeCertificatesInput{},
+			c.deleteGeneric,
+		},
 	}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"strings"
+	"fmt"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/kms"
@@ -9,6 +10,40 @@ import (
 	"github.com/aws/aws-sdk-go/service/efs"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/emr"
+	"strconv"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"crypto/x509"
+	"encoding/pem"
+	"regexp"
+	"time"
+	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/codecommit"
+	"github.com/aws/aws-sdk-go/service/codedeploy"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/glacier"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/waf"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"sync"
+	"github.com/aws/aws-sdk-go/service/opsworks"
+	"sort"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+	"github.com/aws/aws-sdk-go/service/directoryservice"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"reflect"
 )
 
 func (c *WipeCommand) deleteGeneric(res Resources) {
@@ -46,6 +81,27 @@ func (c *WipeCommand) deleteInstances(res Resources) {
 	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags})
 }
 
+func (c *WipeCommand) deleteSpotFleetRequests(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+
+	for _, r := range res.raw.(*ec2.DescribeSpotFleetRequestsOutput).SpotFleetRequestConfigs {
+		state := *r.SpotFleetRequestState
+		if state != "submitted" && state != "active" && state != "modifying" {
+			continue
+		}
+
+		if c.inCfg(res.ttype, r.SpotFleetRequestId) {
+			ids = append(ids, r.SpotFleetRequestId)
+			// instances of the fleet are terminated when the request is cancelled
+			attrs = append(attrs, &map[string]string{
+				"terminate_instances_with_expiration": "true",
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+}
+
 func (c *WipeCommand) deleteInternetGateways(res Resources) {
 	ids := []*string{}
 	attrs := []*map[string]string{}
@@ -68,6 +124,34 @@ func (c *WipeCommand) deleteInternetGateways(res Resources) {
 	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, tags: tags})
 }
 
+func (c *WipeCommand) deleteVpnGateways(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	tags := []*map[string]string{}
+
+	for _, r := range res.raw.(*ec2.DescribeVpnGatewaysOutput).VpnGateways {
+		m := &map[string]string{}
+		for _, t := range r.Tags {
+			(*m)[*t.Key] = *t.Value
+		}
+
+		if c.inCfg(res.ttype, r.VpnGatewayId, m) {
+			// the gateway is detached from its VPC before deletion
+			a := &map[string]string{}
+			for _, va := range r.VpcAttachments {
+				if *va.State == "attached" {
+					(*a)["vpc_id"] = *va.VpcId
+				}
+			}
+
+			ids = append(ids, r.VpnGatewayId)
+			attrs = append(attrs, a)
+			tags = append(tags, m)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, tags: tags})
+}
+
 func (c *WipeCommand) deleteNatGateways(res Resources) {
 	ids := []*string{}
 
@@ -126,6 +210,219 @@ func (c *WipeCommand) deleteRoute53Zone(res Resources) {
 	c.wipe(Resources{ttype: res.ttype, ids: hzIds, attrs: hzAttrs})
 }
 
+// API Gateway allows only a few deletions per minute
+const (
+	apiGatewayRestApiRateLimit    = 30 * time.Second
+	apiGatewayDomainNameRateLimit = 30 * time.Second
+	apiGatewayRateLimit           = 1 * time.Second
+)
+
+func (c *WipeCommand) deleteApiGatewayRestApis(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	apiIds := map[string]bool{}
+
+	for _, r := range res.raw.(*apigateway.GetRestApisOutput).Items {
+		if c.inCfg(res.ttype, r.Id) && c.inNames(res.ttype, r.Name) {
+			ids = append(ids, r.Id)
+			details = append(details, &map[string]string{
+				"name": aws.StringValue(r.Name),
+			})
+			apiIds[*r.Id] = true
+		}
+	}
+
+	// base path mappings pointing to a REST API prevent its deletion
+	bpmIds, bpmAttrs := c.getApiGatewayBasePathMappings(func(domainName string, bpm *apigateway.BasePathMapping) bool {
+		return apiIds[*bpm.RestApiId]
+	})
+
+	c.wipe(Resources{ttype: "aws_api_gateway_base_path_mapping", ids: bpmIds, attrs: bpmAttrs, rateLimit: apiGatewayRateLimit})
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, rateLimit: apiGatewayRestApiRateLimit})
+}
+
+func (c *WipeCommand) deleteApiGatewayDomainNames(res Resources) {
+	ids := []*string{}
+	domainNames := map[string]bool{}
+
+	for _, r := range res.raw.(*apigateway.GetDomainNamesOutput).Items {
+		if c.inCfg(res.ttype, r.DomainName) {
+			ids = append(ids, r.DomainName)
+			domainNames[*r.DomainName] = true
+		}
+	}
+
+	bpmIds, bpmAttrs := c.getApiGatewayBasePathMappings(func(domainName string, bpm *apigateway.BasePathMapping) bool {
+		return domainNames[domainName]
+	})
+
+	c.wipe(Resources{ttype: "aws_api_gateway_base_path_mapping", ids: bpmIds, attrs: bpmAttrs, rateLimit: apiGatewayRateLimit})
+	c.wipe(Resources{ttype: res.ttype, ids: ids, rateLimit: apiGatewayDomainNameRateLimit})
+}
+
+// getApiGatewayBasePathMappings returns all base path mappings for which fn returns true.
+func (c *WipeCommand) getApiGatewayBasePathMappings(fn func(string, *apigateway.BasePathMapping) bool) ([]*string, []*map[string]string) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+
+	dns, err := c.client.apigatewayconn.GetDomainNames(&apigateway.GetDomainNamesInput{
+		Limit: aws.Int64(500),
+	})
+	check(err)
+
+	for _, dn := range dns.Items {
+		bpms, err := c.client.apigatewayconn.GetBasePathMappings(&apigateway.GetBasePathMappingsInput{
+			DomainName: dn.DomainName,
+			Limit:      aws.Int64(500),
+		})
+		if err != nil {
+			continue
+		}
+
+		for _, bpm := range bpms.Items {
+			if fn(*dn.DomainName, bpm) {
+				ids = append(ids, aws.String(*dn.DomainName+"/"+*bpm.BasePath))
+				attrs = append(attrs, &map[string]string{
+					"domain_name": *dn.DomainName,
+					"base_path":   *bpm.BasePath,
+				})
+			}
+		}
+	}
+	return ids, attrs
+}
+
+func (c *WipeCommand) deleteApiGatewayUsagePlans(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	upkIds := []*string{}
+	upkAttrs := []*map[string]string{}
+
+	for _, r := range res.raw.(*apigateway.GetUsagePlansOutput).Items {
+		if c.inCfg(res.ttype, r.Id) && c.inNames(res.ttype, r.Name) {
+			upks, err := c.client.apigatewayconn.GetUsagePlanKeys(&apigateway.GetUsagePlanKeysInput{
+				UsagePlanId: r.Id,
+				Limit:       aws.Int64(500),
+			})
+			if err == nil {
+				for _, upk := range upks.Items {
+					upkIds = append(upkIds, upk.Id)
+					upkAttrs = append(upkAttrs, &map[string]string{
+						"usage_plan_id": *r.Id,
+						"key_id":        *upk.Id,
+						"key_type":      *upk.Type,
+					})
+				}
+			}
+
+			ids = append(ids, r.Id)
+			details = append(details, &map[string]string{
+				"name": aws.StringValue(r.Name),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: "aws_api_gateway_usage_plan_key", ids: upkIds, attrs: upkAttrs, rateLimit: apiGatewayRateLimit})
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, rateLimit: apiGatewayRateLimit})
+}
+
+func (c *WipeCommand) deleteApiGatewayApiKeys(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*apigateway.GetApiKeysOutput).Items {
+		if c.inCfg(res.ttype, r.Id) && c.inNames(res.ttype, r.Name) {
+			ids = append(ids, r.Id)
+			details = append(details, &map[string]string{
+				"name": aws.StringValue(r.Name),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, rateLimit: apiGatewayRateLimit})
+}
+
+func (c *WipeCommand) deleteCloudFrontDistributions(res Resources) {
+	ids := []*string{}
+	tags := []*map[string]string{}
+	details := []*map[string]string{}
+
+	if res.raw.(*cloudfront.ListDistributionsOutput).DistributionList == nil {
+		return
+	}
+
+	for _, r := range res.raw.(*cloudfront.ListDistributionsOutput).DistributionList.Items {
+		m := &map[string]string{}
+		ts, err := c.client.cloudfrontconn.ListTagsForResource(&cloudfront.ListTagsForResourceInput{
+			Resource: r.ARN,
+		})
+		if err == nil {
+			for _, t := range ts.Tags.Items {
+				(*m)[*t.Key] = *t.Value
+			}
+		}
+
+		if c.inCfg(res.ttype, r.Id, m) {
+			ids = append(ids, r.Id)
+			tags = append(tags, m)
+			details = append(details, &map[string]string{
+				"domain_name": *r.DomainName,
+				"enabled":     strconv.FormatBool(*r.Enabled),
+				"status":      *r.Status,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags, details: details, deleteFn: c.deleteCloudFrontDistribution})
+}
+
+// deleteCloudFrontDistribution disables a distribution, waits until the change is deployed
+// and deletes it afterwards. Each step is derived from the current state of the distribution,
+// so that a distribution left behind by an interrupted run is picked up where it stopped.
+func (c *WipeCommand) deleteCloudFrontDistribution(id *string) error {
+	pollInterval := 30 * time.Second
+	timeout := 45 * time.Minute
+
+	start := time.Now()
+	for {
+		d, err := c.client.cloudfrontconn.GetDistribution(&cloudfront.GetDistributionInput{
+			Id: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		elapsed := time.Since(start).Round(time.Second)
+
+		switch {
+		case *d.Distribution.DistributionConfig.Enabled:
+			fmt.Printf("\t%s: disabling distribution (%s)\n", *id, elapsed)
+
+			cfg := d.Distribution.DistributionConfig
+			cfg.Enabled = aws.Bool(false)
+			_, err = c.client.cloudfrontconn.UpdateDistribution(&cloudfront.UpdateDistributionInput{
+				Id:                 id,
+				IfMatch:            d.ETag,
+				DistributionConfig: cfg,
+			})
+			if err != nil {
+				return err
+			}
+		case *d.Distribution.Status == "InProgress":
+			if time.Since(start) > timeout {
+				return fmt.Errorf("%s: distribution is still being deployed, run again to resume", *id)
+			}
+			fmt.Printf("\t%s: waiting for deployment to finish (%s)\n", *id, elapsed)
+			time.Sleep(pollInterval)
+		default:
+			fmt.Printf("\t%s: deleting distribution (%s)\n", *id, elapsed)
+
+			_, err = c.client.cloudfrontconn.DeleteDistribution(&cloudfront.DeleteDistributionInput{
+				Id:      id,
+				IfMatch: d.ETag,
+			})
+			return err
+		}
+	}
+}
+
 func (c *WipeCommand) deleteEfsFileSystem(res Resources) {
 	fsIds := []*string{}
 	mtIds := []*string{}
@@ -150,14 +447,31 @@ func (c *WipeCommand) deleteEfsFileSystem(res Resources) {
 }
 
 func (c *WipeCommand) deleteIamUser(res Resources) {
+	protected := map[string]string{}
 	ids := []*string{}
 	pIds := []*string{}
 	upIds := []*string{}
 	attrs := []*map[string]string{}
 	pAttrs := []*map[string]string{}
+	details := []*map[string]string{}
+	akIds := []*string{}
+	akAttrs := []*map[string]string{}
+	sshIds := []*string{}
+	sshAttrs := []*map[string]string{}
+	scIds := []*string{}
+	mfaIds := []*string{}
+	// user names of signing certificates and MFA devices
+	users := map[string]*string{}
 
 	for _, u := range res.raw.(*iam.ListUsersOutput).Users {
 		if c.inCfg(res.ttype, u.UserName) {
+			if reason := c.isProtected(res.ttype, u.Path, u.UserName); reason != "" {
+				protected[*u.UserName] = reason
+				continue
+			}
+			if !c.isUnused(res.ttype, u.UserName, u.CreateDate, func() ([]*time.Time, error) { return c.getUserLastUsed(u), nil }) {
+				continue
+			}
 
 			// list inline policies, delete with "aws_iam_user_policy" delete routine
 			ups, err := c.client.iamconn.ListUserPolicies(&iam.ListUserPoliciesInput{
@@ -184,25 +498,257 @@ func (c *WipeCommand) deleteIamUser(res Resources) {
 				}
 			}
 
+			// a user can't be deleted as long as it has credentials
+			aks, err := c.client.iamconn.ListAccessKeys(&iam.ListAccessKeysInput{
+				UserName: u.UserName,
+			})
+			check(err)
+
+			for _, ak := range aks.AccessKeyMetadata {
+				akIds = append(akIds, ak.AccessKeyId)
+				akAttrs = append(akAttrs, &map[string]string{
+					"user": *u.UserName,
+				})
+			}
+
+			sshs, err := c.client.iamconn.ListSSHPublicKeys(&iam.ListSSHPublicKeysInput{
+				UserName: u.UserName,
+			})
+			check(err)
+
+			for _, ssh := range sshs.SSHPublicKeys {
+				sshIds = append(sshIds, ssh.SSHPublicKeyId)
+				sshAttrs = append(sshAttrs, &map[string]string{
+					"username": *u.UserName,
+					"encoding": "SSH",
+				})
+			}
+
+			scs, err := c.client.iamconn.ListSigningCertificates(&iam.ListSigningCertificatesInput{
+				UserName: u.UserName,
+			})
+			check(err)
+
+			for _, sc := range scs.Certificates {
+				scIds = append(scIds, sc.CertificateId)
+				users[*sc.CertificateId] = u.UserName
+			}
+
+			mfas, err := c.client.iamconn.ListMFADevices(&iam.ListMFADevicesInput{
+				UserName: u.UserName,
+			})
+			check(err)
+
+			for _, mfa := range mfas.MFADevices {
+				mfaIds = append(mfaIds, mfa.SerialNumber)
+				users[*mfa.SerialNumber] = u.UserName
+			}
+
+			// the login profile and group memberships are removed by the terraform provider (force_destroy)
+			gs, err := c.client.iamconn.ListGroupsForUser(&iam.ListGroupsForUserInput{
+				UserName: u.UserName,
+			})
+			check(err)
+
+			groups := []string{}
+			for _, g := range gs.Groups {
+				groups = append(groups, *g.GroupName)
+			}
+
 			ids = append(ids, u.UserName)
 			attrs = append(attrs, &map[string]string{
 				"force_destroy": "true",
 			})
+
+			d := map[string]string{}
+			if len(groups) > 0 {
+				d["groups"] = strings.Join(groups, ", ")
+			}
+			details = append(details, &d)
 		}
 	}
+	c.printProtected(res.ttype, protected)
+
 	// aws_iam_user_policy to delete inline policies
 	c.wipe(Resources{ttype: "aws_iam_user_policy", ids: upIds})
 	c.wipe(Resources{ttype: "aws_iam_user_policy_attachment", ids: pIds, attrs: pAttrs})
-	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+	c.wipe(Resources{ttype: "aws_iam_access_key", ids: akIds, attrs: akAttrs})
+	c.wipe(Resources{ttype: "aws_iam_user_ssh_key", ids: sshIds, attrs: sshAttrs})
+
+	// signing certificates and MFA devices aren't supported by the terraform provider
+	c.wipe(Resources{ttype: "aws_iam_signing_certificate", ids: scIds, deleteFn: func(id *string) error {
+		_, err := c.client.iamconn.DeleteSigningCertificate(&iam.DeleteSigningCertificateInput{
+			CertificateId: id,
+			UserName:      users[*id],
+		})
+		return err
+	}})
+	c.wipe(Resources{ttype: "aws_iam_mfa_device", ids: mfaIds, deleteFn: func(id *string) error {
+		_, err := c.client.iamconn.DeactivateMFADevice(&iam.DeactivateMFADeviceInput{
+			SerialNumber: id,
+			UserName:     users[*id],
+		})
+		if err != nil {
+			return err
+		}
+
+		// the serial number of a virtual MFA device is its ARN
+		if strings.HasPrefix(*id, "arn:") {
+			_, err = c.client.iamconn.DeleteVirtualMFADevice(&iam.DeleteVirtualMFADeviceInput{
+				SerialNumber: id,
+			})
+		}
+		return err
+	}})
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+// getUserLastUsed returns when a user last signed in and when its access keys were last used.
+func (c *WipeCommand) getUserLastUsed(u *iam.User) []*time.Time {
+	lastUsed := []*time.Time{u.PasswordLastUsed}
+
+	aks, err := c.client.iamconn.ListAccessKeys(&iam.ListAccessKeysInput{
+		UserName: u.UserName,
+	})
+	check(err)
+
+	for _, ak := range aks.AccessKeyMetadata {
+		lastUsed = append(lastUsed, c.getAccessKeyLastUsed(ak.AccessKeyId))
+	}
+	return lastUsed
+}
+
+// deleteIamAccessKeys deletes access keys of all users, so that unused keys
+// can be removed without deleting their users.
+func (c *WipeCommand) deleteIamAccessKeys(res Resources) {
+	protected := map[string]string{}
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+	existing := 0
+
+	for _, u := range res.raw.(*iam.ListUsersOutput).Users {
+		aks, err := c.client.iamconn.ListAccessKeys(&iam.ListAccessKeysInput{
+			UserName: u.UserName,
+		})
+		check(err)
+
+		existing += len(aks.AccessKeyMetadata)
+		for _, ak := range aks.AccessKeyMetadata {
+			if !c.inCfg(res.ttype, ak.AccessKeyId) {
+				continue
+			}
+			if reason := c.isProtected(res.ttype, u.Path, u.UserName); reason != "" {
+				protected[*ak.AccessKeyId] = reason
+				continue
+			}
+
+			if !c.isUnused(res.ttype, ak.AccessKeyId, ak.CreateDate, func() ([]*time.Time, error) {
+				return []*time.Time{c.getAccessKeyLastUsed(ak.AccessKeyId)}, nil
+			}) {
+				continue
+			}
+
+			ids = append(ids, ak.AccessKeyId)
+			attrs = append(attrs, &map[string]string{
+				"user": *u.UserName,
+			})
+
+			d := map[string]string{
+				"user":   *u.UserName,
+				"status": *ak.Status,
+			}
+			if !c.counting {
+				if lastUsed := c.getAccessKeyLastUsed(ak.AccessKeyId); lastUsed != nil {
+					d["last_used"] = lastUsed.Format("2006-01-02")
+				}
+			}
+			details = append(details, &d)
+		}
+	}
+	c.printProtected(res.ttype, protected)
+
+	// the listed resources are users, but max_delete_percent refers to the keys
+	c.existing[res.ttype] = existing
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+func (c *WipeCommand) deleteIamGroups(res Resources) {
+	protected := map[string]string{}
+	ids := []*string{}
+	gpolIds := []*string{}
+	gpolAttrs := []*map[string]string{}
+	gpIds := []*string{}
+	mIds := []*string{}
+	mAttrs := []*map[string]string{}
+
+	for _, g := range res.raw.(*iam.ListGroupsOutput).Groups {
+		if c.inCfg(res.ttype, g.GroupName) {
+			if reason := c.isProtected(res.ttype, g.Path, g.GroupName); reason != "" {
+				protected[*g.GroupName] = reason
+				continue
+			}
+
+			gpols, err := c.client.iamconn.ListAttachedGroupPolicies(&iam.ListAttachedGroupPoliciesInput{
+				GroupName: g.GroupName,
+			})
+			check(err)
+
+			for _, gpol := range gpols.AttachedPolicies {
+				gpolIds = append(gpolIds, gpol.PolicyArn)
+				gpolAttrs = append(gpolAttrs, &map[string]string{
+					"group":      *g.GroupName,
+					"policy_arn": *gpol.PolicyArn,
+				})
+			}
+
+			gps, err := c.client.iamconn.ListGroupPolicies(&iam.ListGroupPoliciesInput{
+				GroupName: g.GroupName,
+			})
+			check(err)
+
+			for _, gp := range gps.PolicyNames {
+				gpIds = append(gpIds, aws.String(*g.GroupName+":"+*gp))
+			}
+
+			// the members of a group are read by the terraform provider and removed all at once
+			out, err := c.client.iamconn.GetGroup(&iam.GetGroupInput{
+				GroupName: g.GroupName,
+			})
+			check(err)
+
+			if len(out.Users) > 0 {
+				mIds = append(mIds, g.GroupName)
+				mAttrs = append(mAttrs, &map[string]string{
+					"group": *g.GroupName,
+				})
+			}
+
+			ids = append(ids, g.GroupName)
+		}
+	}
+
+	c.printProtected(res.ttype, protected)
+
+	c.wipe(Resources{ttype: "aws_iam_group_policy_attachment", ids: gpolIds, attrs: gpolAttrs})
+	c.wipe(Resources{ttype: "aws_iam_group_policy", ids: gpIds})
+	c.wipe(Resources{ttype: "aws_iam_group_membership", ids: mIds, attrs: mAttrs})
+	c.wipe(Resources{ttype: res.ttype, ids: ids})
 }
 
 func (c *WipeCommand) deleteIamPolicy(res Resources) {
+	protected := map[string]string{}
 	ids := []*string{}
 	eIds := []*string{}
 	attributes := []*map[string]string{}
 
 	for _, pol := range res.raw.(*iam.ListPoliciesOutput).Policies {
 		if c.inCfg(res.ttype, pol.Arn) {
+			if reason := c.isProtected(res.ttype, pol.Path, pol.PolicyName); reason != "" {
+				protected[*pol.Arn] = reason
+				continue
+			}
+
 			es, err := c.client.iamconn.ListEntitiesForPolicy(&iam.ListEntitiesForPolicyInput{
 				PolicyArn: pol.Arn,
 			})
@@ -233,6 +779,8 @@ func (c *WipeCommand) deleteIamPolicy(res Resources) {
 			ids = append(ids, pol.Arn)
 		}
 	}
+	c.printProtected(res.ttype, protected)
+
 	// policy attachments are not resources
 	// what happens here, is that policy is detached from groups, users and roles
 	c.wipe(Resources{ttype: "aws_iam_policy_attachment", ids: eIds, attrs: attributes})
@@ -240,6 +788,7 @@ func (c *WipeCommand) deleteIamPolicy(res Resources) {
 }
 
 func (c *WipeCommand) deleteIamRole(res Resources) {
+	protected := map[string]string{}
 	ids := []*string{}
 	rpolIds := []*string{}
 	rpolAttributes := []*map[string]string{}
@@ -247,6 +796,14 @@ func (c *WipeCommand) deleteIamRole(res Resources) {
 
 	for _, role := range res.raw.(*iam.ListRolesOutput).Roles {
 		if c.inCfg(res.ttype, role.RoleName) {
+			if reason := c.isProtected(res.ttype, role.Path, role.RoleName); reason != "" {
+				protected[*role.RoleName] = reason
+				continue
+			}
+			if !c.isUnused(res.ttype, role.RoleName, role.CreateDate, func() ([]*time.Time, error) { return c.getRoleLastUsed(role) }) {
+				continue
+			}
+
 			rpols, err := c.client.iamconn.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
 				RoleName: role.RoleName,
 			})
@@ -283,6 +840,8 @@ func (c *WipeCommand) deleteIamRole(res Resources) {
 		}
 	}
 
+	c.printProtected(res.ttype, protected)
+
 	// aws_iam_policy_attachment could be used to detach a policy from users, groups and roles
 	c.wipe(Resources{ttype: "aws_iam_role_policy_attachment", ids: rpolIds, attrs: rpolAttributes})
 	c.wipe(Resources{ttype: "aws_iam_role_policy", ids: pIds})
@@ -290,11 +849,17 @@ func (c *WipeCommand) deleteIamRole(res Resources) {
 }
 
 func (c *WipeCommand) deleteInstanceProfiles(res Resources) {
+	protected := map[string]string{}
 	ids := []*string{}
 	attributes := []*map[string]string{}
 
 	for _, r := range res.raw.(*iam.ListInstanceProfilesOutput).InstanceProfiles {
 		if c.inCfg(res.ttype, r.InstanceProfileName) {
+			if reason := c.isProtected(res.ttype, r.Path, r.InstanceProfileName); reason != "" {
+				protected[*r.InstanceProfileName] = reason
+				continue
+			}
+
 			ids = append(ids, r.InstanceProfileName)
 
 			roles := []string{}
@@ -307,6 +872,8 @@ func (c *WipeCommand) deleteInstanceProfiles(res Resources) {
 			})
 		}
 	}
+	c.printProtected(res.ttype, protected)
+
 	c.wipe(Resources{ttype: res.ttype, ids: ids})
 }
 
@@ -373,8 +940,1962 @@ func (c *WipeCommand) deleteSnapshots(res Resources) {
 	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags})
 }
 
-func (c *WipeCommand) getAccountId() *string {
-	res, err := c.client.stsconn.GetCallerIdentity(&sts.GetCallerIdentityInput{})
-	check(err)
-	return res.Account
+func (c *WipeCommand) deleteElasticsearchDomains(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	tags := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*elasticsearchservice.ListDomainNamesOutput).DomainNames {
+		d, err := c.client.esconn.DescribeElasticsearchDomain(&elasticsearchservice.DescribeElasticsearchDomainInput{
+			DomainName: r.DomainName,
+		})
+		if err != nil || *d.DomainStatus.Deleted {
+			continue
+		}
+
+		m := &map[string]string{}
+		ts, err := c.client.esconn.ListTags(&elasticsearchservice.ListTagsInput{
+			ARN: d.DomainStatus.ARN,
+		})
+		if err == nil {
+			for _, t := range ts.TagList {
+				(*m)[*t.Key] = *t.Value
+			}
+		}
+
+		if c.inCfg(res.ttype, r.DomainName, m) {
+			cc := d.DomainStatus.ElasticsearchClusterConfig
+
+			ids = append(ids, r.DomainName)
+			attrs = append(attrs, &map[string]string{
+				"domain_name": *r.DomainName,
+			})
+			tags = append(tags, m)
+			details = append(details, &map[string]string{
+				"node_type": aws.StringValue(cc.InstanceType),
+				"nodes":     strconv.FormatInt(aws.Int64Value(cc.InstanceCount), 10),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, tags: tags, details: details})
+}
+
+func (c *WipeCommand) deleteRedshiftClusters(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	tags := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*redshift.DescribeClustersOutput).Clusters {
+		m := &map[string]string{}
+		for _, t := range r.Tags {
+			(*m)[*t.Key] = *t.Value
+		}
+
+		if *r.ClusterStatus != "deleting" && c.inCfg(res.ttype, r.ClusterIdentifier, m) {
+			ids = append(ids, r.ClusterIdentifier)
+			// a final snapshot of a cluster which is swept is of no use
+			attrs = append(attrs, &map[string]string{
+				"skip_final_snapshot": "true",
+			})
+			tags = append(tags, m)
+			details = append(details, &map[string]string{
+				"node_type": *r.NodeType,
+				"nodes":     strconv.FormatInt(*r.NumberOfNodes, 10),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, tags: tags, details: details})
+}
+
+func (c *WipeCommand) deleteEmrClusters(res Resources) {
+	ids := []*string{}
+	tags := []*map[string]string{}
+	details := []*map[string]string{}
+	protectedIds := []*string{}
+
+	for _, r := range res.raw.(*emr.ListClustersOutput).Clusters {
+		cl, err := c.client.emrconn.DescribeCluster(&emr.DescribeClusterInput{
+			ClusterId: r.Id,
+		})
+		if err != nil {
+			continue
+		}
+
+		m := &map[string]string{}
+		for _, t := range cl.Cluster.Tags {
+			(*m)[*t.Key] = *t.Value
+		}
+
+		if c.inCfg(res.ttype, r.Id, m) {
+			nodeTypes := []string{}
+			var nodes int64
+
+			igs, err := c.client.emrconn.ListInstanceGroups(&emr.ListInstanceGroupsInput{
+				ClusterId: r.Id,
+			})
+			if err == nil {
+				for _, ig := range igs.InstanceGroups {
+					nodeTypes = append(nodeTypes, *ig.InstanceGroupType+":"+*ig.InstanceType)
+					nodes += aws.Int64Value(ig.RunningInstanceCount)
+				}
+			}
+
+			if aws.BoolValue(cl.Cluster.TerminationProtected) {
+				protectedIds = append(protectedIds, r.Id)
+			}
+
+			ids = append(ids, r.Id)
+			tags = append(tags, m)
+			details = append(details, &map[string]string{
+				"name":      *r.Name,
+				"node_type": strings.Join(nodeTypes, ","),
+				"nodes":     strconv.FormatInt(nodes, 10),
+			})
+		}
+	}
+
+	// protected clusters can't be terminated
+	if !c.dryRun && len(protectedIds) > 0 {
+		_, err := c.client.emrconn.SetTerminationProtection(&emr.SetTerminationProtectionInput{
+			JobFlowIds:           protectedIds,
+			TerminationProtected: aws.Bool(false),
+		})
+		if err != nil {
+			fmt.Printf("\t%s\n", err)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags, details: details})
+}
+
+func (c *WipeCommand) deleteAcmCertificates(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	skipped := map[string]string{}
+
+	for _, r := range res.raw.(*acm.ListCertificatesOutput).CertificateSummaryList {
+		if !c.inCfg(res.ttype, r.CertificateArn) {
+			continue
+		}
+
+		d, err := c.client.acmconn.DescribeCertificate(&acm.DescribeCertificateInput{
+			CertificateArn: r.CertificateArn,
+		})
+		if err != nil {
+			continue
+		}
+		cert := d.Certificate
+
+		domainNames := append([]string{*cert.DomainName}, aws.StringValueSlice(cert.SubjectAlternativeNames)...)
+		if !c.matchesCertFilter(res.ttype, domainNames, cert.NotAfter) {
+			continue
+		}
+
+		inUseBy := aws.StringValueSlice(cert.InUseBy)
+		if len(inUseBy) > 0 && !c.deleteCfg[res.ttype].IncludeInUse {
+			skipped[*r.CertificateArn] = strings.Join(inUseBy, ", ")
+			continue
+		}
+
+		ids = append(ids, r.CertificateArn)
+		details = append(details, certDetails(*cert.DomainName, cert.NotAfter, inUseBy))
+	}
+	c.printSkipped(res.ttype, skipped)
+
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, deleteFn: func(id *string) error {
+		_, err := c.client.acmconn.DeleteCertificate(&acm.DeleteCertificateInput{
+			CertificateArn: id,
+		})
+		return err
+	}})
+}
+
+func (c *WipeCommand) deleteIamServerCertificates(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+	skipped := map[string]string{}
+
+	inUse, err := c.getServerCertificateUses()
+	if err != nil {
+		// without knowing which certificates are in use, none are deleted
+		fmt.Printf("\t%s\n", err)
+		return
+	}
+
+	for _, r := range res.raw.(*iam.ListServerCertificatesOutput).ServerCertificateMetadataList {
+		if !c.inCfg(res.ttype, r.ServerCertificateName) {
+			continue
+		}
+
+		domainNames := []string{}
+		if len(c.deleteCfg[res.ttype].DomainNames) > 0 {
+			sc, err := c.client.iamconn.GetServerCertificate(&iam.GetServerCertificateInput{
+				ServerCertificateName: r.ServerCertificateName,
+			})
+			if err != nil {
+				continue
+			}
+			domainNames = certDomainNames(*sc.ServerCertificate.CertificateBody)
+		}
+		if !c.matchesCertFilter(res.ttype, domainNames, r.Expiration) {
+			continue
+		}
+
+		inUseBy := append(inUse[*r.Arn], inUse[*r.ServerCertificateId]...)
+		if len(inUseBy) > 0 && !c.deleteCfg[res.ttype].IncludeInUse {
+			skipped[*r.ServerCertificateName] = strings.Join(inUseBy, ", ")
+			continue
+		}
+
+		ids = append(ids, r.ServerCertificateName)
+		attrs = append(attrs, &map[string]string{
+			"name": *r.ServerCertificateName,
+		})
+		details = append(details, certDetails(strings.Join(domainNames, ","), r.Expiration, inUseBy))
+	}
+	c.printSkipped(res.ttype, skipped)
+
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+// getServerCertificateUses returns the names of the load balancers and the IDs of the CloudFront distributions
+// using server certificates. Load balancers refer to certificates by ARN, distributions by ID.
+func (c *WipeCommand) getServerCertificateUses() (map[string][]string, error) {
+	inUse := map[string][]string{}
+
+	err := c.client.elbconn.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range page.LoadBalancerDescriptions {
+				for _, l := range lb.ListenerDescriptions {
+					if l.Listener.SSLCertificateId != nil {
+						inUse[*l.Listener.SSLCertificateId] = append(inUse[*l.Listener.SSLCertificateId], *lb.LoadBalancerName)
+					}
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	lbs := []*elbv2.LoadBalancer{}
+	err = c.client.elbv2conn.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			lbs = append(lbs, page.LoadBalancers...)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lb := range lbs {
+		err = c.client.elbv2conn.DescribeListenersPages(&elbv2.DescribeListenersInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+		}, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+			for _, l := range page.Listeners {
+				for _, cert := range l.Certificates {
+					if cert.CertificateArn != nil {
+						inUse[*cert.CertificateArn] = append(inUse[*cert.CertificateArn], *lb.LoadBalancerName)
+					}
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.client.cloudfrontconn.ListDistributionsPages(&cloudfront.ListDistributionsInput{},
+		func(page *cloudfront.ListDistributionsOutput, lastPage bool) bool {
+			for _, d := range page.DistributionList.Items {
+				if d.ViewerCertificate != nil && d.ViewerCertificate.IAMCertificateId != nil {
+					id := *d.ViewerCertificate.IAMCertificateId
+					inUse[id] = append(inUse[id], *d.Id)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return inUse, nil
+}
+
+// matchesCertFilter checks the domain names and the expiry date of a certificate
+// against the filters in the yaml configuration.
+func (c *WipeCommand) matchesCertFilter(rType string, domainNames []string, notAfter *time.Time) bool {
+	cfgVal := c.deleteCfg[rType]
+
+	if len(cfgVal.DomainNames) > 0 {
+		matches := false
+		for _, regex := range cfgVal.DomainNames {
+			for _, dn := range domainNames {
+				if ok, _ := regexp.MatchString(*regex, dn); ok {
+					matches = true
+				}
+			}
+		}
+		if !matches {
+			return false
+		}
+	}
+
+	if cfgVal.ExpiresBefore != "" {
+		// the date is validated in Run
+		expiresBefore, _ := parseExpiresBefore(cfgVal.ExpiresBefore)
+		if notAfter == nil || !notAfter.Before(expiresBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseExpiresBefore parses a date (YYYY-MM-DD) or "now".
+func parseExpiresBefore(date string) (time.Time, error) {
+	if date == "now" {
+		return time.Now(), nil
+	}
+	return time.Parse("2006-01-02", date)
+}
+
+// certDomainNames returns the common name and the alternative names of a PEM encoded certificate.
+func certDomainNames(body string) []string {
+	block, _ := pem.Decode([]byte(body))
+	if block == nil {
+		return []string{}
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return []string{}
+	}
+	return append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+}
+
+func certDetails(domainName string, notAfter *time.Time, inUseBy []string) *map[string]string {
+	d := &map[string]string{
+		"domain_name": domainName,
+	}
+	if notAfter != nil {
+		(*d)["expires"] = notAfter.Format("2006-01-02")
+	}
+	if len(inUseBy) > 0 {
+		(*d)["in_use_by"] = strings.Join(inUseBy, ", ")
+	}
+	return d
+}
+
+// printSkipped reports resources that match the filter, but are left untouched
+// because they are in use.
+func (c *WipeCommand) printSkipped(ttype string, skipped map[string]string) {
+	if len(skipped) == 0 || c.counting {
+		return
+	}
+
+	fmt.Printf("\n---\nType: %s\nSkipped (in use): %d\n\n", ttype, len(skipped))
+	for id, by := range skipped {
+		fmt.Printf("\tId:\t%s\n\tUsed by:\t%s\n\n", id, by)
+	}
+	fmt.Print("---\n\n")
+}
+
+// printListingFailed reports resources that are skipped, as they couldn't be listed
+// (e.g. missing permissions or a service that isn't available in the region).
+func (c *WipeCommand) printListingFailed(ttype string, err error) {
+	if c.counting {
+		return
+	}
+	fmt.Printf("\n---\nType: %s\nSkipped, listing failed:\n\t%s\n---\n\n", ttype, err)
+}
+
+// printProtected reports resources that match the filter, but are left untouched
+// because they are protected.
+func (c *WipeCommand) printProtected(ttype string, protected map[string]string) {
+	if len(protected) == 0 || c.counting {
+		return
+	}
+
+	fmt.Printf("\n---\nType: %s\nSkipped (protected): %d\n\n", ttype, len(protected))
+	for id, reason := range protected {
+		fmt.Printf("\tId:\t%s\n\tProtected by:\t%s\n\n", id, reason)
+	}
+	fmt.Print("---\n\n")
+}
+
+func (c *WipeCommand) deleteKinesisStreams(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	tags := []*map[string]string{}
+
+	for _, r := range res.raw.(*kinesis.ListStreamsOutput).StreamNames {
+		m := &map[string]string{}
+		ts, err := c.client.kinesisconn.ListTagsForStream(&kinesis.ListTagsForStreamInput{
+			StreamName: r,
+		})
+		if err == nil {
+			for _, t := range ts.Tags {
+				(*m)[*t.Key] = aws.StringValue(t.Value)
+			}
+		}
+
+		if c.inCfg(res.ttype, r, m) {
+			ids = append(ids, r)
+			attrs = append(attrs, &map[string]string{
+				"name": *r,
+			})
+			tags = append(tags, m)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, tags: tags})
+}
+
+func (c *WipeCommand) deleteFirehoseDeliveryStreams(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+
+	for _, r := range res.raw.(*firehose.ListDeliveryStreamsOutput).DeliveryStreamNames {
+		if c.inCfg(res.ttype, r) {
+			ids = append(ids, r)
+			attrs = append(attrs, &map[string]string{
+				"name": *r,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+}
+
+func (c *WipeCommand) deleteSfnStateMachines(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	executionArns := []*string{}
+
+	for _, r := range res.raw.(*sfn.ListStateMachinesOutput).StateMachines {
+		if c.inCfg(res.ttype, r.StateMachineArn) && c.inNames(res.ttype, r.Name) {
+			es, err := c.client.sfnconn.ListExecutions(&sfn.ListExecutionsInput{
+				StateMachineArn: r.StateMachineArn,
+				StatusFilter:    aws.String(sfn.ExecutionStatusRunning),
+			})
+			running := 0
+			if err == nil {
+				running = len(es.Executions)
+				for _, e := range es.Executions {
+					executionArns = append(executionArns, e.ExecutionArn)
+				}
+			}
+
+			ids = append(ids, r.StateMachineArn)
+			details = append(details, &map[string]string{
+				"name":               *r.Name,
+				"running_executions": strconv.Itoa(running),
+			})
+		}
+	}
+
+	// executions of a deleted state machine keep running until they complete
+	if c.deleteCfg[res.ttype].StopExecutions && !c.dryRun {
+		for _, arn := range executionArns {
+			_, err := c.client.sfnconn.StopExecution(&sfn.StopExecutionInput{
+				ExecutionArn: arn,
+				Cause:        aws.String("state machine is deleted by awsweeper"),
+			})
+			if err != nil {
+				fmt.Printf("\t%s\n", err)
+			}
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) deleteSfnActivities(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*sfn.ListActivitiesOutput).Activities {
+		if c.inCfg(res.ttype, r.ActivityArn) && c.inNames(res.ttype, r.Name) {
+			ids = append(ids, r.ActivityArn)
+			details = append(details, &map[string]string{
+				"name": *r.Name,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) deleteCodeDeployApps(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+
+	for _, r := range res.raw.(*codedeploy.ListApplicationsOutput).Applications {
+		if c.inCfg(res.ttype, r) {
+			app, err := c.client.codedeployconn.GetApplication(&codedeploy.GetApplicationInput{
+				ApplicationName: r,
+			})
+			if err != nil {
+				continue
+			}
+
+			// the terraform ID of an application is "<id>:<name>"
+			ids = append(ids, aws.String(*app.Application.ApplicationId+":"+*r))
+			attrs = append(attrs, &map[string]string{
+				"name": *r,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+}
+
+func (c *WipeCommand) deleteCodeDeployDeploymentGroups(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	existing := 0
+
+	for _, app := range res.raw.(*codedeploy.ListApplicationsOutput).Applications {
+		dgs, err := c.client.codedeployconn.ListDeploymentGroups(&codedeploy.ListDeploymentGroupsInput{
+			ApplicationName: app,
+		})
+		if err != nil {
+			continue
+		}
+
+		existing += len(dgs.DeploymentGroups)
+		for _, dg := range dgs.DeploymentGroups {
+			if c.inCfg(res.ttype, dg) {
+				ids = append(ids, aws.String(*app+":"+*dg))
+				attrs = append(attrs, &map[string]string{
+					"app_name":              *app,
+					"deployment_group_name": *dg,
+				})
+			}
+		}
+	}
+	// the listed resources are applications
+	c.existing[res.ttype] = existing
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+}
+
+func (c *WipeCommand) deleteCodeCommitRepositories(res Resources) {
+	ids := []*string{}
+
+	for _, r := range res.raw.(*codecommit.ListRepositoriesOutput).Repositories {
+		if c.inCfg(res.ttype, r.RepositoryName) {
+			ids = append(ids, r.RepositoryName)
+		}
+	}
+
+	if len(ids) > 0 && !c.deleteCfg[res.ttype].DeleteSourceCode {
+		if c.counting {
+			return
+		}
+		fmt.Printf("\n---\nType: %s\nFound: %d\n\n", res.ttype, len(ids))
+		for _, id := range ids {
+			fmt.Printf("\tId:\t%s\n\n", *id)
+		}
+		fmt.Printf("Skipped, as deleting repositories loses source code. Set 'delete_source_code: true' to delete them.\n---\n\n")
+		return
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids})
+}
+
+func (c *WipeCommand) deleteSsmAssociations(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*ssm.ListAssociationsOutput).Associations {
+		if c.inCfg(res.ttype, r.AssociationId) {
+			ids = append(ids, r.AssociationId)
+			attrs = append(attrs, &map[string]string{
+				"association_id": *r.AssociationId,
+			})
+			details = append(details, &map[string]string{
+				"name": aws.StringValue(r.Name),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+func (c *WipeCommand) deleteSsmDocuments(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*ssm.ListDocumentsOutput).DocumentIdentifiers {
+		if c.inCfg(res.ttype, r.Name) {
+			ids = append(ids, r.Name)
+			// the terraform provider reads and deletes documents by name, not by ID
+			attrs = append(attrs, &map[string]string{
+				"name": *r.Name,
+			})
+			details = append(details, &map[string]string{
+				"document_type": aws.StringValue(r.DocumentType),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+// SSM allows only a few parameters to be deleted per second
+const ssmParameterRateLimit = 200 * time.Millisecond
+
+func (c *WipeCommand) deleteSsmParameters(res Resources) {
+	ids := []*string{}
+	prefixes := c.deleteCfg[res.ttype].PathPrefixes
+	existing := 0
+
+	out := res.raw.(*ssm.DescribeParametersOutput)
+	for {
+		existing += len(out.Parameters)
+		for _, r := range out.Parameters {
+			if !c.inCfg(res.ttype, r.Name) {
+				continue
+			}
+
+			inPath := len(prefixes) == 0
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(*r.Name, *prefix) {
+					inPath = true
+				}
+			}
+
+			if inPath {
+				ids = append(ids, r.Name)
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+
+		var err error
+		out, err = c.client.ssmconn.DescribeParameters(&ssm.DescribeParametersInput{
+			MaxResults: aws.Int64(50),
+			NextToken:  out.NextToken,
+		})
+		check(err)
+	}
+	// the listing only contains the first page
+	c.existing[res.ttype] = existing
+
+	// parameters aren't supported by the terraform provider
+	c.wipe(Resources{ttype: res.ttype, ids: ids, rateLimit: ssmParameterRateLimit, deleteFn: func(id *string) error {
+		_, err := c.client.ssmconn.DeleteParameter(&ssm.DeleteParameterInput{
+			Name: id,
+		})
+		return err
+	}})
+}
+
+func (c *WipeCommand) deleteBeanstalkEnvironments(res Resources) {
+	envs := []*elasticbeanstalk.EnvironmentDescription{}
+
+	for _, r := range res.raw.(*elasticbeanstalk.EnvironmentDescriptionsMessage).Environments {
+		if c.inCfg(res.ttype, r.EnvironmentId) && c.inNames(res.ttype, r.EnvironmentName) {
+			envs = append(envs, r)
+		}
+	}
+	c.wipeBeanstalkEnvironments(envs)
+}
+
+func (c *WipeCommand) wipeBeanstalkEnvironments(envs []*elasticbeanstalk.EnvironmentDescription) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, env := range envs {
+		if *env.Status == "Terminating" || *env.Status == "Terminated" {
+			continue
+		}
+
+		ids = append(ids, env.EnvironmentId)
+		attrs = append(attrs, &map[string]string{
+			"wait_for_ready_timeout": "20m",
+		})
+		details = append(details, &map[string]string{
+			"name":        *env.EnvironmentName,
+			"application": *env.ApplicationName,
+		})
+	}
+	c.wipe(Resources{ttype: "aws_elastic_beanstalk_environment", ids: ids, attrs: attrs, details: details})
+}
+
+func (c *WipeCommand) deleteBeanstalkApplications(res Resources) {
+	ids := []*string{}
+	envs := []*elasticbeanstalk.EnvironmentDescription{}
+
+	for _, r := range res.raw.(*elasticbeanstalk.DescribeApplicationsOutput).Applications {
+		if c.inCfg(res.ttype, r.ApplicationName) {
+			es, err := c.client.beanstalkconn.DescribeEnvironments(&elasticbeanstalk.DescribeEnvironmentsInput{
+				ApplicationName: r.ApplicationName,
+				IncludeDeleted:  aws.Bool(false),
+			})
+			check(err)
+
+			envs = append(envs, es.Environments...)
+			ids = append(ids, r.ApplicationName)
+		}
+	}
+
+	// environments are terminated asynchronously,
+	// an application can only be deleted once all of its environments are gone
+	c.wipeBeanstalkEnvironments(envs)
+	if !c.dryRun {
+		for _, id := range ids {
+			c.waitForBeanstalkEnvironments(id)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids})
+}
+
+func (c *WipeCommand) waitForBeanstalkEnvironments(app *string) {
+	pollInterval := 15 * time.Second
+	timeout := 30 * time.Minute
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		es, err := c.client.beanstalkconn.DescribeEnvironments(&elasticbeanstalk.DescribeEnvironmentsInput{
+			ApplicationName: app,
+			IncludeDeleted:  aws.Bool(false),
+		})
+		if err != nil || len(es.Environments) == 0 {
+			return
+		}
+
+		fmt.Printf("\t%s: waiting for %d environment(s) to terminate (%s)\n",
+			*app, len(es.Environments), time.Since(start).Round(time.Second))
+		time.Sleep(pollInterval)
+	}
+}
+
+func (c *WipeCommand) deleteBeanstalkApplicationVersions(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	skipped := map[string]string{}
+	versions := map[string]*elasticbeanstalk.ApplicationVersionDescription{}
+
+	// versions deployed to an environment can't be deleted
+	inUse := map[string][]string{}
+	es, err := c.client.beanstalkconn.DescribeEnvironments(&elasticbeanstalk.DescribeEnvironmentsInput{
+		IncludeDeleted: aws.Bool(false),
+	})
+	check(err)
+
+	for _, env := range es.Environments {
+		if env.VersionLabel != nil {
+			key := *env.ApplicationName + "/" + *env.VersionLabel
+			inUse[key] = append(inUse[key], *env.EnvironmentName)
+		}
+	}
+
+	for _, r := range res.raw.(*elasticbeanstalk.DescribeApplicationVersionsOutput).ApplicationVersions {
+		if !c.inCfg(res.ttype, r.VersionLabel) {
+			continue
+		}
+
+		id := *r.ApplicationName + "/" + *r.VersionLabel
+		if len(inUse[id]) > 0 {
+			skipped[id] = strings.Join(inUse[id], ", ")
+			continue
+		}
+
+		versions[id] = r
+		ids = append(ids, aws.String(id))
+		details = append(details, &map[string]string{
+			"created": r.DateCreated.Format("2006-01-02"),
+		})
+	}
+	c.printSkipped(res.ttype, skipped)
+
+	// the terraform provider never deletes the source bundle of a version
+	deleteSourceBundle := c.deleteCfg[res.ttype].DeleteSourceBundle
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, deleteFn: func(id *string) error {
+		v := versions[*id]
+		_, err := c.client.beanstalkconn.DeleteApplicationVersion(&elasticbeanstalk.DeleteApplicationVersionInput{
+			ApplicationName:    v.ApplicationName,
+			VersionLabel:       v.VersionLabel,
+			DeleteSourceBundle: aws.Bool(deleteSourceBundle),
+		})
+		return err
+	}})
+}
+
+func (c *WipeCommand) deleteGlacierVaults(res Resources) {
+	ids := []*string{}
+	tags := []*map[string]string{}
+	details := []*map[string]string{}
+	skipped := map[string]string{}
+
+	for _, r := range res.raw.(*glacier.ListVaultsOutput).VaultList {
+		m := &map[string]string{}
+		ts, err := c.client.glacierconn.ListTagsForVault(&glacier.ListTagsForVaultInput{
+			AccountId: aws.String("-"),
+			VaultName: r.VaultName,
+		})
+		if err == nil {
+			for k, v := range ts.Tags {
+				(*m)[k] = aws.StringValue(v)
+			}
+		}
+
+		if c.inCfg(res.ttype, r.VaultName, m) {
+			// the number of archives is as of the last inventory of the vault.
+			// Vaults with archives can't be deleted
+			archives := aws.Int64Value(r.NumberOfArchives)
+			if archives > 0 {
+				skipped[*r.VaultName] = fmt.Sprintf("%d archive(s)", archives)
+				continue
+			}
+
+			ids = append(ids, r.VaultName)
+			tags = append(tags, m)
+			details = append(details, &map[string]string{
+				"archives":       strconv.FormatInt(archives, 10),
+				"size_bytes":     strconv.FormatInt(aws.Int64Value(r.SizeInBytes), 10),
+				"last_inventory": aws.StringValue(r.LastInventoryDate),
+			})
+		}
+	}
+
+	c.printSkipped(res.ttype, skipped)
+
+	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags, details: details})
+}
+
+func (c *WipeCommand) deleteSesDomainIdentities(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+
+	for _, r := range res.raw.(*ses.ListIdentitiesOutput).Identities {
+		if c.inCfg(res.ttype, r) {
+			ids = append(ids, r)
+			attrs = append(attrs, &map[string]string{
+				"domain": *r,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs})
+}
+
+func (c *WipeCommand) deleteSesReceiptRuleSets(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	active, err := c.client.sesconn.DescribeActiveReceiptRuleSet(&ses.DescribeActiveReceiptRuleSetInput{})
+	check(err)
+
+	activeName := ""
+	if active.Metadata != nil {
+		activeName = *active.Metadata.Name
+	}
+
+	for _, r := range res.raw.(*ses.ListReceiptRuleSetsOutput).RuleSets {
+		if c.inCfg(res.ttype, r.Name) {
+			ids = append(ids, r.Name)
+			details = append(details, &map[string]string{
+				"active": strconv.FormatBool(*r.Name == activeName),
+			})
+
+			// the active rule set can't be deleted
+			if *r.Name == activeName && !c.dryRun {
+				_, err := c.client.sesconn.SetActiveReceiptRuleSet(&ses.SetActiveReceiptRuleSetInput{})
+				if err != nil {
+					fmt.Printf("\t%s\n", err)
+				}
+			}
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) getAccountId() *string {
+	res, err := c.client.stsconn.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	check(err)
+	return res.Account
+}
+
+// WAF changes need a fresh change token each, and a token goes stale
+// as soon as another change is made with it
+const wafMaxRetries = 10
+
+var wafMutex sync.Mutex
+
+// withWafChangeToken calls fn with a new change token, retrying while the token is stale.
+func (c *WipeCommand) withWafChangeToken(fn func(token *string) error) error {
+	wafMutex.Lock()
+	defer wafMutex.Unlock()
+
+	var err error
+	for i := 0; i < wafMaxRetries; i++ {
+		var out *waf.GetChangeTokenOutput
+		out, err = c.client.wafconn.GetChangeToken(&waf.GetChangeTokenInput{})
+		if err != nil {
+			return err
+		}
+
+		err = fn(out.ChangeToken)
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != "WAFStaleDataException" {
+			return err
+		}
+		time.Sleep(1 * time.Second)
+	}
+	return err
+}
+
+func (c *WipeCommand) deleteWafWebAcls(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*waf.ListWebACLsOutput).WebACLs {
+		if c.inCfg(res.ttype, r.WebACLId) {
+			ids = append(ids, r.WebACLId)
+			details = append(details, &map[string]string{"name": *r.Name})
+		}
+	}
+
+	// the terraform provider doesn't read the rules of a web ACL,
+	// so they are removed here before the web ACL is deleted
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, deleteFn: func(id *string) error {
+		out, err := c.client.wafconn.GetWebACL(&waf.GetWebACLInput{
+			WebACLId: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		updates := []*waf.WebACLUpdate{}
+		for _, r := range out.WebACL.Rules {
+			updates = append(updates, &waf.WebACLUpdate{
+				Action:        aws.String(waf.ChangeActionDelete),
+				ActivatedRule: r,
+			})
+		}
+
+		if len(updates) > 0 {
+			err = c.withWafChangeToken(func(token *string) error {
+				_, err := c.client.wafconn.UpdateWebACL(&waf.UpdateWebACLInput{
+					ChangeToken: token,
+					WebACLId:    id,
+					Updates:     updates,
+				})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return c.withWafChangeToken(func(token *string) error {
+			_, err := c.client.wafconn.DeleteWebACL(&waf.DeleteWebACLInput{
+				ChangeToken: token,
+				WebACLId:    id,
+			})
+			return err
+		})
+	}})
+}
+
+func (c *WipeCommand) deleteWafSqlInjectionMatchSets(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*waf.ListSqlInjectionMatchSetsOutput).SqlInjectionMatchSets {
+		if c.inCfg(res.ttype, r.SqlInjectionMatchSetId) {
+			ids = append(ids, r.SqlInjectionMatchSetId)
+			details = append(details, &map[string]string{"name": *r.Name})
+		}
+	}
+
+	// the terraform provider fails to read the tuples of a SQL injection match set,
+	// so the set is emptied here before it is deleted
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details, deleteFn: func(id *string) error {
+		out, err := c.client.wafconn.GetSqlInjectionMatchSet(&waf.GetSqlInjectionMatchSetInput{
+			SqlInjectionMatchSetId: id,
+		})
+		if err != nil {
+			return err
+		}
+
+		updates := []*waf.SqlInjectionMatchSetUpdate{}
+		for _, t := range out.SqlInjectionMatchSet.SqlInjectionMatchTuples {
+			updates = append(updates, &waf.SqlInjectionMatchSetUpdate{
+				Action:                 aws.String(waf.ChangeActionDelete),
+				SqlInjectionMatchTuple: t,
+			})
+		}
+
+		if len(updates) > 0 {
+			err = c.withWafChangeToken(func(token *string) error {
+				_, err := c.client.wafconn.UpdateSqlInjectionMatchSet(&waf.UpdateSqlInjectionMatchSetInput{
+					ChangeToken:            token,
+					SqlInjectionMatchSetId: id,
+					Updates:                updates,
+				})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return c.withWafChangeToken(func(token *string) error {
+			_, err := c.client.wafconn.DeleteSqlInjectionMatchSet(&waf.DeleteSqlInjectionMatchSetInput{
+				ChangeToken:            token,
+				SqlInjectionMatchSetId: id,
+			})
+			return err
+		})
+	}})
+}
+
+// OpsWorks layer types and the terraform types to delete them with
+var opsWorksLayerTypes = map[string]string{
+	"custom":            "aws_opsworks_custom_layer",
+	"monitoring-master": "aws_opsworks_ganglia_layer",
+	"lb":                "aws_opsworks_haproxy_layer",
+	"java-app":          "aws_opsworks_java_app_layer",
+	"memcached":         "aws_opsworks_memcached_layer",
+	"db-master":         "aws_opsworks_mysql_layer",
+	"nodejs-app":        "aws_opsworks_nodejs_app_layer",
+	"php-app":           "aws_opsworks_php_app_layer",
+	"rails-app":         "aws_opsworks_rails_app_layer",
+	"web":               "aws_opsworks_static_web_layer",
+}
+
+// deleteOpsWorksStacks deletes stacks together with their instances, apps and layers,
+// as a stack can't be deleted as long as it has any of them.
+func (c *WipeCommand) deleteOpsWorksStacks(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	instanceIds := []*string{}
+	instanceAttrs := []*map[string]string{}
+	runningIds := []*string{}
+	appIds := []*string{}
+	layerIds := map[string][]*string{}
+
+	for _, s := range res.raw.(*opsworks.DescribeStacksOutput).Stacks {
+		if !c.inCfg(res.ttype, s.StackId) || !c.inNames(res.ttype, s.Name) || !c.inRegions(res.ttype, s.Region) {
+			continue
+		}
+
+		is, err := c.client.opsworksconn.DescribeInstances(&opsworks.DescribeInstancesInput{
+			StackId: s.StackId,
+		})
+		check(err)
+
+		for _, i := range is.Instances {
+			instanceIds = append(instanceIds, i.InstanceId)
+			instanceAttrs = append(instanceAttrs, &map[string]string{
+				"delete_ebs": "true",
+				"delete_eip": "true",
+			})
+			if *i.Status != "stopped" {
+				runningIds = append(runningIds, i.InstanceId)
+			}
+		}
+
+		as, err := c.client.opsworksconn.DescribeApps(&opsworks.DescribeAppsInput{
+			StackId: s.StackId,
+		})
+		check(err)
+
+		for _, a := range as.Apps {
+			appIds = append(appIds, a.AppId)
+		}
+
+		ls, err := c.client.opsworksconn.DescribeLayers(&opsworks.DescribeLayersInput{
+			StackId: s.StackId,
+		})
+		check(err)
+
+		for _, l := range ls.Layers {
+			ttype, ok := opsWorksLayerTypes[*l.Type]
+			if !ok {
+				ttype = "aws_opsworks_custom_layer"
+			}
+			layerIds[ttype] = append(layerIds[ttype], l.LayerId)
+		}
+
+		ids = append(ids, s.StackId)
+		details = append(details, &map[string]string{
+			"name":      *s.Name,
+			"region":    *s.Region,
+			"instances": strconv.Itoa(len(is.Instances)),
+			"apps":      strconv.Itoa(len(as.Apps)),
+			"layers":    strconv.Itoa(len(ls.Layers)),
+		})
+	}
+
+	// instances have to be stopped before they can be deleted
+	if !c.dryRun && len(runningIds) > 0 {
+		for _, id := range runningIds {
+			_, err := c.client.opsworksconn.StopInstance(&opsworks.StopInstanceInput{
+				InstanceId: id,
+			})
+			if err != nil {
+				fmt.Printf("\t%s\n", err)
+			}
+		}
+		c.waitForOpsWorksInstances(ids, "stopped", func(i *opsworks.Instance) bool {
+			return *i.Status == "stopped"
+		})
+	}
+	c.wipe(Resources{ttype: "aws_opsworks_instance", ids: instanceIds, attrs: instanceAttrs})
+
+	// layers can't be deleted as long as they have instances
+	if !c.dryRun && len(instanceIds) > 0 {
+		c.waitForOpsWorksInstances(ids, "deleted", func(i *opsworks.Instance) bool {
+			return false
+		})
+	}
+	c.wipe(Resources{ttype: "aws_opsworks_application", ids: appIds})
+
+	layerTypes := []string{}
+	for ttype := range layerIds {
+		layerTypes = append(layerTypes, ttype)
+	}
+	sort.Strings(layerTypes)
+
+	for _, ttype := range layerTypes {
+		c.wipe(Resources{ttype: ttype, ids: layerIds[ttype]})
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+// waitForOpsWorksInstances waits until fn returns true for all instances of the given stacks.
+func (c *WipeCommand) waitForOpsWorksInstances(stackIds []*string, state string, fn func(i *opsworks.Instance) bool) {
+	pollInterval := 15 * time.Second
+	timeout := 20 * time.Minute
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		pending := 0
+		for _, id := range stackIds {
+			is, err := c.client.opsworksconn.DescribeInstances(&opsworks.DescribeInstancesInput{
+				StackId: id,
+			})
+			if err != nil {
+				continue
+			}
+			for _, i := range is.Instances {
+				if !fn(i) {
+					pending++
+				}
+			}
+		}
+		if pending == 0 {
+			return
+		}
+
+		fmt.Printf("\twaiting for %d OpsWorks instance(s) to be %s (%s)\n",
+			pending, state, time.Since(start).Round(time.Second))
+		time.Sleep(pollInterval)
+	}
+}
+
+func (c *WipeCommand) deleteCognitoIdentityPools(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*cognitoidentity.ListIdentityPoolsOutput).IdentityPools {
+		if c.inCfg(res.ttype, r.IdentityPoolId) && c.inNames(res.ttype, r.IdentityPoolName) {
+			ids = append(ids, r.IdentityPoolId)
+			details = append(details, &map[string]string{"name": *r.IdentityPoolName})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+// deleteDirectories deletes directories and waits until they are gone, which can take a while.
+func (c *WipeCommand) deleteDirectories(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*directoryservice.DescribeDirectoriesOutput).DirectoryDescriptions {
+		if *r.Stage == directoryservice.DirectoryStageDeleted || *r.Stage == directoryservice.DirectoryStageDeleting {
+			continue
+		}
+		if c.inCfg(res.ttype, r.DirectoryId) && c.inNames(res.ttype, r.Name) {
+			ids = append(ids, r.DirectoryId)
+
+			d := map[string]string{
+				"name": *r.Name,
+				"type": *r.Type,
+			}
+			if r.Size != nil {
+				d["size"] = *r.Size
+			}
+			details = append(details, &d)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) deleteLightsailInstances(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*lightsail.GetInstancesOutput).Instances {
+		if c.inCfg(res.ttype, r.Name) {
+			ids = append(ids, r.Name)
+			details = append(details, &map[string]string{
+				"bundle":    *r.BundleId,
+				"blueprint": *r.BlueprintId,
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) deleteLightsailStaticIps(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*lightsail.GetStaticIpsOutput).StaticIps {
+		if c.inCfg(res.ttype, r.Name) {
+			ids = append(ids, r.Name)
+			// the terraform provider reads and releases static IPs by their name attribute
+			attrs = append(attrs, &map[string]string{"name": *r.Name})
+
+			d := map[string]string{"ip": *r.IpAddress}
+			if r.IsAttached != nil && *r.IsAttached {
+				d["attached_to"] = *r.AttachedTo
+			}
+			details = append(details, &d)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+// deleteDmsReplicationTasks stops running replication tasks, as they can't be deleted otherwise.
+func (c *WipeCommand) deleteDmsReplicationTasks(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+	runningArns := []*string{}
+
+	for _, r := range res.raw.(*databasemigrationservice.DescribeReplicationTasksOutput).ReplicationTasks {
+		if c.inCfg(res.ttype, r.ReplicationTaskIdentifier) {
+			ids = append(ids, r.ReplicationTaskIdentifier)
+			details = append(details, &map[string]string{
+				"migration_type": *r.MigrationType,
+				"status":         *r.Status,
+			})
+			if *r.Status == "running" {
+				runningArns = append(runningArns, r.ReplicationTaskArn)
+			}
+		}
+	}
+
+	if !c.dryRun && len(runningArns) > 0 {
+		for _, arn := range runningArns {
+			_, err := c.client.dmsconn.StopReplicationTask(&databasemigrationservice.StopReplicationTaskInput{
+				ReplicationTaskArn: arn,
+			})
+			if err != nil {
+				fmt.Printf("\t%s\n", err)
+			}
+		}
+		c.waitForDmsReplicationTasks(runningArns)
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) waitForDmsReplicationTasks(arns []*string) {
+	pollInterval := 15 * time.Second
+	timeout := 20 * time.Minute
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		ts, err := c.client.dmsconn.DescribeReplicationTasks(&databasemigrationservice.DescribeReplicationTasksInput{
+			Filters: []*databasemigrationservice.Filter{
+				{
+					Name:   aws.String("replication-task-arn"),
+					Values: arns,
+				},
+			},
+		})
+		if err != nil {
+			return
+		}
+
+		pending := 0
+		for _, t := range ts.ReplicationTasks {
+			if *t.Status == "running" || *t.Status == "stopping" {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return
+		}
+
+		fmt.Printf("\twaiting for %d replication task(s) to stop (%s)\n",
+			pending, time.Since(start).Round(time.Second))
+		time.Sleep(pollInterval)
+	}
+}
+
+func (c *WipeCommand) deleteDmsReplicationInstances(res Resources) {
+	ids := []*string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*databasemigrationservice.DescribeReplicationInstancesOutput).ReplicationInstances {
+		if c.inCfg(res.ttype, r.ReplicationInstanceIdentifier) {
+			ids = append(ids, r.ReplicationInstanceIdentifier)
+			details = append(details, &map[string]string{
+				"instance_class":    *r.ReplicationInstanceClass,
+				"allocated_storage": strconv.FormatInt(*r.AllocatedStorage, 10),
+				"multi_az":          strconv.FormatBool(*r.MultiAZ),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, details: details})
+}
+
+func (c *WipeCommand) deleteVpcs(res Resources) {
+	ids := []*string{}
+	tags := []*map[string]string{}
+
+	for i, r := range res.ids {
+		if c.inCfg(res.ttype, r, res.tags[i]) {
+			ids = append(ids, r)
+			tags = append(tags, res.tags[i])
+		}
+	}
+
+	if c.deleteCfg[res.ttype].Cascade {
+		for _, id := range ids {
+			c.wipeVpcDependents(id)
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags})
+}
+
+// wipeVpcDependents deletes all resources inside a VPC in the order of their dependencies,
+// after printing them as a tree.
+func (c *WipeCommand) wipeVpcDependents(vpcId *string) {
+	deps := c.getVpcDependents(vpcId)
+
+	if !c.counting {
+		fmt.Printf("\n---\nVPC: %s\n", *vpcId)
+		for _, dep := range deps {
+			if len(dep.ids) == 0 {
+				continue
+			}
+			fmt.Printf("\t%s: %d\n", dep.ttype, len(dep.ids))
+			for _, id := range dep.ids {
+				fmt.Printf("\t\t%s\n", *id)
+			}
+		}
+		fmt.Print("---\n\n")
+	}
+
+	var lambdaIds []*string
+	for _, dep := range deps {
+		switch dep.ttype {
+		case "aws_lambda_function_vpc_config":
+			lambdaIds = dep.ids
+		case "aws_network_interface":
+			// ENIs of detached Lambda functions are released asynchronously
+			if !c.dryRun && len(lambdaIds) > 0 {
+				c.waitForLambdaNetworkInterfaces(vpcId)
+			}
+			// ENIs of deleted instances, load balancers and NAT gateways are gone by now
+			enis, err := c.getVpcNetworkInterfaces(vpcId)
+			if err != nil {
+				c.printListingFailed(dep.ttype, err)
+				continue
+			}
+			dep = enis
+		case "aws_security_group":
+			sgs, err := c.getVpcSecurityGroups(vpcId)
+			if err != nil {
+				c.printListingFailed(dep.ttype, err)
+				continue
+			}
+			c.revokeSecurityGroupReferences(dep.ids, sgs)
+		}
+		c.wipe(dep)
+	}
+}
+
+// getVpcDependents returns the resources inside a VPC in the order they have to be deleted.
+// Dependents that can't be listed are reported and left out.
+func (c *WipeCommand) getVpcDependents(vpcId *string) []Resources {
+	deps := []Resources{}
+	vpcFilter := []*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{vpcId},
+		},
+	}
+
+	instanceIds := []*string{}
+	err := c.client.ec2conn.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: append(vpcFilter, &ec2.Filter{
+			Name:   aws.String("instance-state-name"),
+			Values: aws.StringSlice([]string{"pending", "running", "stopping", "stopped"}),
+		}),
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, r := range page.Reservations {
+			for _, in := range r.Instances {
+				instanceIds = append(instanceIds, in.InstanceId)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		c.printListingFailed("aws_instance", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_instance", ids: instanceIds})
+	}
+
+	// Lambda functions are only detached from the VPC, so that their ENIs are released
+	fnIds := []*string{}
+	err = c.client.lambdaconn.ListFunctionsPages(&lambda.ListFunctionsInput{},
+		func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+			for _, fn := range page.Functions {
+				if fn.VpcConfig != nil && fn.VpcConfig.VpcId != nil && *fn.VpcConfig.VpcId == *vpcId {
+					fnIds = append(fnIds, fn.FunctionName)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		c.printListingFailed("aws_lambda_function_vpc_config", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_lambda_function_vpc_config", ids: fnIds, deleteFn: func(id *string) error {
+			_, err := c.client.lambdaconn.UpdateFunctionConfiguration(&lambda.UpdateFunctionConfigurationInput{
+				FunctionName: id,
+				VpcConfig: &lambda.VpcConfig{
+					SubnetIds:        []*string{},
+					SecurityGroupIds: []*string{},
+				},
+			})
+			return err
+		}})
+	}
+
+	elbIds := []*string{}
+	err = c.client.elbconn.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range page.LoadBalancerDescriptions {
+				if lb.VPCId != nil && *lb.VPCId == *vpcId {
+					elbIds = append(elbIds, lb.LoadBalancerName)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		c.printListingFailed("aws_elb", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_elb", ids: elbIds})
+	}
+
+	albIds := []*string{}
+	err = c.client.elbv2conn.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
+		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+			for _, lb := range page.LoadBalancers {
+				if lb.VpcId != nil && *lb.VpcId == *vpcId {
+					albIds = append(albIds, lb.LoadBalancerArn)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		c.printListingFailed("aws_alb", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_alb", ids: albIds})
+	}
+
+	dbIds := []*string{}
+	dbAttrs := []*map[string]string{}
+	err = c.client.rdsconn.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{},
+		func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+			for _, db := range page.DBInstances {
+				if db.DBSubnetGroup != nil && db.DBSubnetGroup.VpcId != nil && *db.DBSubnetGroup.VpcId == *vpcId {
+					dbIds = append(dbIds, db.DBInstanceIdentifier)
+					dbAttrs = append(dbAttrs, dbInstanceAttrs(db.DBInstanceIdentifier, c.deleteCfg["aws_vpc"].SkipFinalSnapshot))
+				}
+			}
+			return true
+		})
+	if err != nil {
+		c.printListingFailed("aws_db_instance", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_db_instance", ids: dbIds, attrs: dbAttrs})
+	}
+
+	sgIds := []*string{}
+	err = c.client.rdsconn.DescribeDBSubnetGroupsPages(&rds.DescribeDBSubnetGroupsInput{},
+		func(page *rds.DescribeDBSubnetGroupsOutput, lastPage bool) bool {
+			for _, sg := range page.DBSubnetGroups {
+				if sg.VpcId != nil && *sg.VpcId == *vpcId {
+					sgIds = append(sgIds, sg.DBSubnetGroupName)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		c.printListingFailed("aws_db_subnet_group", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_db_subnet_group", ids: sgIds})
+	}
+
+	natIds := []*string{}
+	err = c.client.ec2conn.DescribeNatGatewaysPages(&ec2.DescribeNatGatewaysInput{
+		Filter: vpcFilter,
+	}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+		for _, nat := range page.NatGateways {
+			if *nat.State == "pending" || *nat.State == "available" {
+				natIds = append(natIds, nat.NatGatewayId)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		c.printListingFailed("aws_nat_gateway", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_nat_gateway", ids: natIds})
+	}
+
+	eps, err := c.client.ec2conn.DescribeVpcEndpoints(&ec2.DescribeVpcEndpointsInput{
+		Filters: vpcFilter,
+	})
+	if err != nil {
+		c.printListingFailed("aws_vpc_endpoint", err)
+	} else {
+		epIds := []*string{}
+		for _, ep := range eps.VpcEndpoints {
+			if *ep.State != "deleted" && *ep.State != "deleting" {
+				epIds = append(epIds, ep.VpcEndpointId)
+			}
+		}
+		deps = append(deps, Resources{ttype: "aws_vpc_endpoint", ids: epIds})
+	}
+
+	if enis, err := c.getVpcNetworkInterfaces(vpcId); err != nil {
+		c.printListingFailed("aws_network_interface", err)
+	} else {
+		deps = append(deps, enis)
+	}
+
+	sgs, err := c.getVpcSecurityGroups(vpcId)
+	if err != nil {
+		c.printListingFailed("aws_security_group", err)
+	} else {
+		groupIds := []*string{}
+		for _, sg := range sgs {
+			if *sg.GroupName != "default" {
+				groupIds = append(groupIds, sg.GroupId)
+			}
+		}
+		deps = append(deps, Resources{ttype: "aws_security_group", ids: groupIds})
+	}
+
+	subnets, err := c.client.ec2conn.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: vpcFilter,
+	})
+	if err != nil {
+		c.printListingFailed("aws_subnet", err)
+	} else {
+		subnetIds := []*string{}
+		for _, s := range subnets.Subnets {
+			subnetIds = append(subnetIds, s.SubnetId)
+		}
+		deps = append(deps, Resources{ttype: "aws_subnet", ids: subnetIds})
+	}
+
+	rts, err := c.client.ec2conn.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: vpcFilter,
+	})
+	if err != nil {
+		c.printListingFailed("aws_route_table", err)
+	} else {
+		rtIds := []*string{}
+		for _, rt := range rts.RouteTables {
+			if getDefault(reflect.ValueOf(rt)) == "" {
+				rtIds = append(rtIds, rt.RouteTableId)
+			}
+		}
+		deps = append(deps, Resources{ttype: "aws_route_table", ids: rtIds})
+	}
+
+	acls, err := c.client.ec2conn.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{
+		Filters: vpcFilter,
+	})
+	if err != nil {
+		c.printListingFailed("aws_network_acl", err)
+	} else {
+		aclIds := []*string{}
+		for _, acl := range acls.NetworkAcls {
+			if !*acl.IsDefault {
+				aclIds = append(aclIds, acl.NetworkAclId)
+			}
+		}
+		deps = append(deps, Resources{ttype: "aws_network_acl", ids: aclIds})
+	}
+
+	igws, err := c.client.ec2conn.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("attachment.vpc-id"),
+				Values: []*string{vpcId},
+			},
+		},
+	})
+	if err != nil {
+		c.printListingFailed("aws_internet_gateway", err)
+	} else {
+		igwIds := []*string{}
+		igwAttrs := []*map[string]string{}
+		for _, igw := range igws.InternetGateways {
+			igwIds = append(igwIds, igw.InternetGatewayId)
+			igwAttrs = append(igwAttrs, &map[string]string{
+				"vpc_id": *vpcId,
+			})
+		}
+		deps = append(deps, Resources{ttype: "aws_internet_gateway", ids: igwIds, attrs: igwAttrs})
+	}
+
+	// VPN gateways are only detached, as they may be attached to another VPC later
+	vgws, err := c.client.ec2conn.DescribeVpnGateways(&ec2.DescribeVpnGatewaysInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("attachment.vpc-id"),
+				Values: []*string{vpcId},
+			},
+			{
+				Name:   aws.String("attachment.state"),
+				Values: aws.StringSlice([]string{"attaching", "attached"}),
+			},
+		},
+	})
+	if err != nil {
+		c.printListingFailed("aws_vpn_gateway_attachment", err)
+	} else {
+		vgwIds := []*string{}
+		for _, vgw := range vgws.VpnGateways {
+			vgwIds = append(vgwIds, vgw.VpnGatewayId)
+		}
+		deps = append(deps, Resources{ttype: "aws_vpn_gateway_attachment", ids: vgwIds, deleteFn: func(id *string) error {
+			_, err := c.client.ec2conn.DetachVpnGateway(&ec2.DetachVpnGatewayInput{
+				VpcId:        vpcId,
+				VpnGatewayId: id,
+			})
+			if err != nil {
+				return err
+			}
+			return c.waitForVpnGatewayDetachment(id, vpcId)
+		}})
+	}
+
+	eigws, err := c.client.ec2conn.DescribeEgressOnlyInternetGateways(&ec2.DescribeEgressOnlyInternetGatewaysInput{})
+	if err != nil {
+		c.printListingFailed("aws_egress_only_internet_gateway", err)
+	} else {
+		eigwIds := []*string{}
+		for _, eigw := range eigws.EgressOnlyInternetGateways {
+			for _, a := range eigw.Attachments {
+				if *a.VpcId == *vpcId {
+					eigwIds = append(eigwIds, eigw.EgressOnlyInternetGatewayId)
+				}
+			}
+		}
+		deps = append(deps, Resources{ttype: "aws_egress_only_internet_gateway", ids: eigwIds})
+	}
+
+	// the VPC can be either side of a peering connection
+	pcIds := []*string{}
+	seen := map[string]bool{}
+	for _, side := range []string{"requester-vpc-info.vpc-id", "accepter-vpc-info.vpc-id"} {
+		var pcs *ec2.DescribeVpcPeeringConnectionsOutput
+		pcs, err = c.client.ec2conn.DescribeVpcPeeringConnections(&ec2.DescribeVpcPeeringConnectionsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String(side),
+					Values: []*string{vpcId},
+				},
+				{
+					Name:   aws.String("status-code"),
+					Values: aws.StringSlice([]string{"initiating-request", "pending-acceptance", "provisioning", "active"}),
+				},
+			},
+		})
+		if err != nil {
+			break
+		}
+
+		for _, pc := range pcs.VpcPeeringConnections {
+			if !seen[*pc.VpcPeeringConnectionId] {
+				seen[*pc.VpcPeeringConnectionId] = true
+				pcIds = append(pcIds, pc.VpcPeeringConnectionId)
+			}
+		}
+	}
+	if err != nil {
+		c.printListingFailed("aws_vpc_peering_connection", err)
+	} else {
+		deps = append(deps, Resources{ttype: "aws_vpc_peering_connection", ids: pcIds})
+	}
+
+	return deps
+}
+
+func (c *WipeCommand) deleteSecurityGroups(res Resources) {
+	ids := []*string{}
+	tags := []*map[string]string{}
+
+	for i, r := range res.ids {
+		if c.inCfg(res.ttype, r, res.tags[i]) {
+			ids = append(ids, r)
+			tags = append(tags, res.tags[i])
+		}
+	}
+
+	// groups referenced by rules of other groups (or of each other) can't be deleted
+	if len(ids) > 0 {
+		c.revokeSecurityGroupReferences(ids, res.raw.(*ec2.DescribeSecurityGroupsOutput).SecurityGroups)
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, tags: tags})
+}
+
+// getVpcNetworkInterfaces returns the ENIs inside a VPC that can be deleted by the user.
+func (c *WipeCommand) getVpcNetworkInterfaces(vpcId *string) (Resources, error) {
+	ids := []*string{}
+
+	enis, err := c.client.ec2conn.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{vpcId},
+			},
+		},
+	})
+	if err != nil {
+		return Resources{}, err
+	}
+
+	for _, eni := range enis.NetworkInterfaces {
+		// ENIs of instances are deleted together with them
+		if eni.Attachment != nil && eni.Attachment.DeleteOnTermination != nil && *eni.Attachment.DeleteOnTermination {
+			continue
+		}
+		// ENIs managed by AWS services can't be deleted, except the ones left behind by Lambda functions
+		isLambdaEni := eni.Description != nil && strings.HasPrefix(*eni.Description, "AWS Lambda VPC ENI")
+		if eni.RequesterManaged != nil && *eni.RequesterManaged && !isLambdaEni {
+			continue
+		}
+		ids = append(ids, eni.NetworkInterfaceId)
+	}
+	return Resources{ttype: "aws_network_interface", ids: ids}, nil
+}
+
+// waitForLambdaNetworkInterfaces waits until the ENIs of Lambda functions detached from a VPC
+// aren't in use anymore, which can take a while.
+func (c *WipeCommand) waitForLambdaNetworkInterfaces(vpcId *string) {
+	pollInterval := 15 * time.Second
+	timeout := 20 * time.Minute
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		enis, err := c.client.ec2conn.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []*string{vpcId},
+				},
+				{
+					Name:   aws.String("description"),
+					Values: aws.StringSlice([]string{"AWS Lambda VPC ENI*"}),
+				},
+				{
+					Name:   aws.String("status"),
+					Values: aws.StringSlice([]string{"in-use"}),
+				},
+			},
+		})
+		if err != nil || len(enis.NetworkInterfaces) == 0 {
+			return
+		}
+
+		fmt.Printf("\twaiting for %d Lambda ENI(s) to be released (%s)\n",
+			len(enis.NetworkInterfaces), time.Since(start).Round(time.Second))
+		time.Sleep(pollInterval)
+	}
+}
+
+// waitForVpnGatewayDetachment waits until a VPN gateway is detached from a VPC.
+func (c *WipeCommand) waitForVpnGatewayDetachment(vgwId *string, vpcId *string) error {
+	pollInterval := 10 * time.Second
+	timeout := 10 * time.Minute
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		vgws, err := c.client.ec2conn.DescribeVpnGateways(&ec2.DescribeVpnGatewaysInput{
+			VpnGatewayIds: []*string{vgwId},
+		})
+		if err != nil {
+			return err
+		}
+
+		detached := true
+		for _, vgw := range vgws.VpnGateways {
+			for _, a := range vgw.VpcAttachments {
+				if *a.VpcId == *vpcId && *a.State != ec2.AttachmentStatusDetached {
+					detached = false
+				}
+			}
+		}
+		if detached {
+			return nil
+		}
+		time.Sleep(pollInterval)
+	}
+	return fmt.Errorf("%s: still detaching from %s", *vgwId, *vpcId)
+}
+
+func (c *WipeCommand) getVpcSecurityGroups(vpcId *string) ([]*ec2.SecurityGroup, error) {
+	sgs, err := c.client.ec2conn.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{vpcId},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sgs.SecurityGroups, nil
+}
+
+// revokeSecurityGroupReferences revokes all rules of the given groups that refer to one of
+// the groups about to be deleted, as a group can't be deleted as long as it is referenced.
+// The revoked rules are printed, so that they can be recreated if needed.
+func (c *WipeCommand) revokeSecurityGroupReferences(ids []*string, groups []*ec2.SecurityGroup) {
+	deleted := map[string]bool{}
+	for _, id := range ids {
+		deleted[*id] = true
+	}
+
+	revoked := ""
+	for _, g := range groups {
+		ingress := getReferencingPermissions(g.IpPermissions, deleted)
+		egress := getReferencingPermissions(g.IpPermissionsEgress, deleted)
+		if len(ingress) == 0 && len(egress) == 0 {
+			continue
+		}
+
+		revoked += fmt.Sprintf("\tId:\t%s\n", *g.GroupId)
+		for _, p := range ingress {
+			revoked += fmt.Sprintf("\tIngress:\t%s\n", formatPermission(p))
+		}
+		for _, p := range egress {
+			revoked += fmt.Sprintf("\tEgress:\t%s\n", formatPermission(p))
+		}
+		revoked += "\n"
+
+		if c.dryRun {
+			continue
+		}
+		if len(ingress) > 0 {
+			_, err := c.client.ec2conn.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       g.GroupId,
+				IpPermissions: ingress,
+			})
+			if err != nil {
+				fmt.Printf("\t%s\n", err)
+			}
+		}
+		if len(egress) > 0 {
+			_, err := c.client.ec2conn.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       g.GroupId,
+				IpPermissions: egress,
+			})
+			if err != nil {
+				fmt.Printf("\t%s\n", err)
+			}
+		}
+	}
+
+	if revoked != "" && !c.counting {
+		msg := "Revoked rules referring to deleted groups"
+		if c.dryRun {
+			msg = "Rules referring to deleted groups that would be revoked"
+		}
+		fmt.Printf("\n---\nType: aws_security_group\n%s:\n\n%s---\n\n", msg, revoked)
+	}
+}
+
+// getReferencingPermissions returns the parts of the permissions that refer to one of the given groups.
+func getReferencingPermissions(perms []*ec2.IpPermission, groupIds map[string]bool) []*ec2.IpPermission {
+	refs := []*ec2.IpPermission{}
+
+	for _, p := range perms {
+		pairs := []*ec2.UserIdGroupPair{}
+		for _, pair := range p.UserIdGroupPairs {
+			if pair.GroupId != nil && groupIds[*pair.GroupId] {
+				pairs = append(pairs, pair)
+			}
+		}
+
+		if len(pairs) > 0 {
+			refs = append(refs, &ec2.IpPermission{
+				IpProtocol:       p.IpProtocol,
+				FromPort:         p.FromPort,
+				ToPort:           p.ToPort,
+				UserIdGroupPairs: pairs,
+			})
+		}
+	}
+	return refs
+}
+
+func formatPermission(p *ec2.IpPermission) string {
+	ports := "all"
+	if p.FromPort != nil && p.ToPort != nil {
+		ports = fmt.Sprintf("%d-%d", *p.FromPort, *p.ToPort)
+	}
+
+	groups := []string{}
+	for _, pair := range p.UserIdGroupPairs {
+		groups = append(groups, *pair.GroupId)
+	}
+	return fmt.Sprintf("[protocol: %s] [ports: %s] [groups: %s]", *p.IpProtocol, ports, strings.Join(groups, ", "))
+}
+
+func (c *WipeCommand) deleteDbInstances(res Resources) {
+	ids := []*string{}
+	attrs := []*map[string]string{}
+	details := []*map[string]string{}
+
+	for _, r := range res.raw.(*rds.DescribeDBInstancesOutput).DBInstances {
+		if c.inCfg(res.ttype, r.DBInstanceIdentifier) {
+			ids = append(ids, r.DBInstanceIdentifier)
+			attrs = append(attrs, dbInstanceAttrs(r.DBInstanceIdentifier, c.deleteCfg[res.ttype].SkipFinalSnapshot))
+			details = append(details, &map[string]string{
+				"instance_class": *r.DBInstanceClass,
+				"engine":         *r.Engine,
+				"multi_az":       strconv.FormatBool(*r.MultiAZ),
+			})
+		}
+	}
+	c.wipe(Resources{ttype: res.ttype, ids: ids, attrs: attrs, details: details})
+}
+
+// dbInstanceAttrs returns the attributes to delete an RDS instance with, which takes
+// a final snapshot unless skipFinalSnapshot is set.
+func dbInstanceAttrs(id *string, skipFinalSnapshot bool) *map[string]string {
+	if skipFinalSnapshot {
+		return &map[string]string{
+			"skip_final_snapshot": "true",
+		}
+	}
+	return &map[string]string{
+		"skip_final_snapshot":       "false",
+		"final_snapshot_identifier": *id + "-final-" + time.Now().Format("20060102150405"),
+	}
 }